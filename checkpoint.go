@@ -0,0 +1,337 @@
+package mRMR
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/gob"
+	"fmt"
+	"hash"
+	"hash/fnv"
+	"math"
+	"os"
+	"path/filepath"
+)
+
+const checkpointVersion = 2
+
+// checkpoint is the selection state saved to CheckpointPath, together with the parameters
+// and a fingerprint of the data it was computed from.
+type checkpoint struct {
+	Version     int
+	Fingerprint uint64
+
+	Method            string
+	Calculation       string
+	RedundancyMethod  string
+	Discretization    bool
+	BinSize           int
+	MaxFeatures       int
+	Threshold         float64
+	CostMode          string
+	CostLambda        float64
+	Budget            float64
+	GroupMode         string
+	MaxPerGroup       int
+	LabelAggregation  string
+	RedundancyMemory  int
+	TieBreak          string
+	Seed              int64
+	Relief            string
+	ReliefNeighbors   int
+	ReliefSamples     int
+	MINormalization   string
+	Estimator         string
+	Prune             string
+	PruneCandidates   int
+	MICAlpha          float64
+	MICClumps         float64
+	HSICApproximation string
+	HSICComponents    int
+	Costs             []float64
+	Groups            []int
+	CustomRelevance   bool // the run used its own RelevanceFunc, which cannot be saved
+	CustomRedundancy  bool
+
+	Relevance  []float64
+	Candidates []int
+	Selected   []int
+	Spent      float64
+	GroupCount map[int]int
+	LastScore  float64
+	Covered    []int
+
+	StoreFeatures int
+	StoreMaxRows  int
+	StoreSelected []int
+	StoreRows     [][]float64
+	StoreSum      []float64
+	StoreMax      []float64
+	StoreCount    int
+}
+
+// checkpointer saves the selection state of a run every few steps, and holds the state it resumes from.
+type checkpointer struct {
+	path        string
+	every       int
+	fingerprint uint64
+	resume      *checkpoint
+}
+
+// Resume continues an MRMR run from a checkpoint written to CheckpointPath.
+// The parameters of the interrupted run, including Costs and Groups, are restored from the checkpoint.
+// Functions cannot be saved, so paras needs the same Data, the RelevanceFunc and RedundancyFunc of the run if it set
+// its own, and the same RegisterMethod registration for a registered method. An error is returned if the data has
+// changed or a custom measure is missing. Checkpoints keep being written if CheckpointPath is set.
+func (paras *ParasmRMR) Resume(ctx context.Context, path string) ([]int, []float64, map[[2]int]float64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("unable to open checkpoint %s: %w", path, err)
+	}
+	defer file.Close()
+
+	cp := &checkpoint{}
+	if err := gob.NewDecoder(file).Decode(cp); err != nil {
+		return nil, nil, nil, fmt.Errorf("error reading checkpoint %s: %w", path, err)
+	}
+
+	if cp.Version != checkpointVersion {
+		return nil, nil, nil, fmt.Errorf("checkpoint %s has version %d, expected %d", path, cp.Version, checkpointVersion)
+	}
+
+	if cp.CustomRelevance && paras.RelevanceFunc == nil {
+		return nil, nil, nil, fmt.Errorf("checkpoint %s was written with a custom RelevanceFunc, which must be set again", path)
+	}
+
+	if cp.CustomRedundancy && paras.RedundancyFunc == nil {
+		return nil, nil, nil, fmt.Errorf("checkpoint %s was written with a custom RedundancyFunc, which must be set again", path)
+	}
+
+	run := *paras
+	cp.restore(&run)
+
+	if fingerprint := run.fingerprint(); fingerprint != cp.Fingerprint {
+		return nil, nil, nil, fmt.Errorf("data does not match checkpoint %s: fingerprint %x, expected %x", path, fingerprint, cp.Fingerprint)
+	}
+
+	return run.run(ctx, &checkpointer{
+		path:        paras.CheckpointPath,
+		fingerprint: cp.Fingerprint,
+		resume:      cp,
+	})
+}
+
+// due reports whether a checkpoint should be written after the given number of steps.
+func (ck *checkpointer) due(steps int) bool {
+	return ck.path != "" && ck.every > 0 && steps%ck.every == 0
+}
+
+// save writes the state to the checkpoint path, replacing the previous checkpoint atomically.
+func (ck *checkpointer) save(paras *ParasmRMR, state *selectionState) error {
+	if ck.path == "" {
+		return nil
+	}
+
+	cp := newCheckpoint(paras, state)
+	cp.Fingerprint = ck.fingerprint
+
+	tmp, err := os.CreateTemp(filepath.Dir(ck.path), filepath.Base(ck.path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("unable to write checkpoint: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := gob.NewEncoder(tmp).Encode(cp); err != nil {
+		tmp.Close()
+		return fmt.Errorf("unable to write checkpoint: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("unable to write checkpoint: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), ck.path); err != nil {
+		return fmt.Errorf("unable to write checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+func newCheckpoint(paras *ParasmRMR, state *selectionState) *checkpoint {
+	return &checkpoint{
+		Version: checkpointVersion,
+
+		Method:            string(paras.Method),
+		Calculation:       string(paras.Calculation),
+		RedundancyMethod:  string(paras.RedundancyMethod),
+		Discretization:    paras.Discretization,
+		BinSize:           paras.BinSize,
+		MaxFeatures:       paras.MaxFeatures,
+		Threshold:         paras.Threshold,
+		CostMode:          paras.CostMode,
+		CostLambda:        paras.CostLambda,
+		Budget:            paras.Budget,
+		GroupMode:         paras.GroupMode,
+		MaxPerGroup:       paras.MaxPerGroup,
+		LabelAggregation:  paras.LabelAggregation,
+		RedundancyMemory:  paras.RedundancyMemory,
+		TieBreak:          paras.TieBreak,
+		Seed:              paras.Seed,
+		Relief:            paras.Relief,
+		ReliefNeighbors:   paras.ReliefNeighbors,
+		ReliefSamples:     paras.ReliefSamples,
+		MINormalization:   paras.MINormalization,
+		Estimator:         paras.Estimator,
+		Prune:             paras.Prune,
+		PruneCandidates:   paras.PruneCandidates,
+		MICAlpha:          paras.MICAlpha,
+		MICClumps:         paras.MICClumps,
+		HSICApproximation: paras.HSICApproximation,
+		HSICComponents:    paras.HSICComponents,
+		Costs:             paras.Costs,
+		Groups:            paras.Groups,
+		CustomRelevance:   !paras.methodRelevance,
+		CustomRedundancy:  !paras.methodRedundancy,
+
+		Relevance:  state.relevance,
+		Candidates: state.candidates,
+		Selected:   state.selected,
+		Spent:      state.spent,
+		GroupCount: state.groupCount,
+		LastScore:  state.lastScore,
+		Covered:    state.covered,
+
+		StoreFeatures: state.store.numFeatures,
+		StoreMaxRows:  state.store.maxRows,
+		StoreSelected: state.store.selected,
+		StoreRows:     state.store.rows,
+		StoreSum:      state.store.sum,
+		StoreMax:      state.store.max,
+		StoreCount:    state.store.count,
+	}
+}
+
+// restore sets the parameters of the checkpointed run.
+func (cp *checkpoint) restore(paras *ParasmRMR) {
+	paras.Method = Method(cp.Method)
+	paras.Calculation = Calculation(cp.Calculation)
+	paras.RedundancyMethod = RedundancyMethod(cp.RedundancyMethod)
+	paras.Discretization = cp.Discretization
+	paras.BinSize = cp.BinSize
+	paras.MaxFeatures = cp.MaxFeatures
+	paras.Threshold = cp.Threshold
+	paras.thresholdSet = true
+	paras.CostMode = cp.CostMode
+	paras.CostLambda = cp.CostLambda
+	paras.Budget = cp.Budget
+	paras.GroupMode = cp.GroupMode
+	paras.MaxPerGroup = cp.MaxPerGroup
+	paras.LabelAggregation = cp.LabelAggregation
+	paras.RedundancyMemory = cp.RedundancyMemory
+	paras.TieBreak = cp.TieBreak
+	paras.Seed = cp.Seed
+	paras.Relief = cp.Relief
+	paras.ReliefNeighbors = cp.ReliefNeighbors
+	paras.ReliefSamples = cp.ReliefSamples
+	paras.MINormalization = cp.MINormalization
+	paras.Estimator = cp.Estimator
+	paras.Prune = cp.Prune
+	paras.PruneCandidates = cp.PruneCandidates
+	paras.MICAlpha = cp.MICAlpha
+	paras.MICClumps = cp.MICClumps
+	paras.HSICApproximation = cp.HSICApproximation
+	paras.HSICComponents = cp.HSICComponents
+	paras.Costs = cp.Costs
+	paras.Groups = cp.Groups
+}
+
+// state rebuilds the selection state.
+func (cp *checkpoint) state() *selectionState {
+	groupCount := cp.GroupCount
+	if groupCount == nil {
+		groupCount = make(map[int]int)
+	}
+
+	return &selectionState{
+		relevance:  cp.Relevance,
+		candidates: cp.Candidates,
+		selected:   cp.Selected,
+		spent:      cp.Spent,
+		groupCount: groupCount,
+		lastScore:  cp.LastScore,
+		covered:    cp.Covered,
+		store: &RedundancyStore{
+			numFeatures: cp.StoreFeatures,
+			maxRows:     cp.StoreMaxRows,
+			selected:    cp.StoreSelected,
+			rows:        cp.StoreRows,
+			sum:         cp.StoreSum,
+			max:         cp.StoreMax,
+			count:       cp.StoreCount,
+		},
+	}
+}
+
+// fingerprint hashes the input data, costs and groups, so a checkpoint is only resumed on the data it came from.
+func (paras *ParasmRMR) fingerprint() uint64 {
+	h := fnv.New64a()
+	data := paras.Data
+
+	rows, cols := data.dims()
+	hashInts(h, []int{rows, cols})
+
+	switch {
+	case data.Sparse != nil:
+		hashInts(h, data.Sparse.ColPtr)
+		hashInts(h, data.Sparse.RowIdx)
+		hashFloats(h, data.Sparse.Values)
+	case data.Store != nil:
+		var buf []float64
+		for j := 0; j < cols; j++ {
+			buf = data.Store.Column(j, buf)
+			hashFloats(h, buf)
+		}
+		hashInts(h, data.Store.Class())
+	case data.Matrix != nil:
+		for j := 0; j < cols; j++ {
+			hashFloats(h, data.Matrix.column(j))
+		}
+	default:
+		for _, row := range data.X {
+			hashFloats(h, row)
+		}
+	}
+
+	hashInts(h, data.Class)
+	for _, row := range data.Labels {
+		hashInts(h, row)
+	}
+
+	hashFloats(h, paras.Costs)
+	hashInts(h, paras.Groups)
+
+	return h.Sum64()
+}
+
+func hashFloats(h hash.Hash64, data []float64) {
+	buf := make([]byte, 8)
+
+	binary.LittleEndian.PutUint64(buf, uint64(len(data)))
+	h.Write(buf)
+
+	for _, val := range data {
+		binary.LittleEndian.PutUint64(buf, math.Float64bits(val))
+		h.Write(buf)
+	}
+}
+
+func hashInts(h hash.Hash64, data []int) {
+	buf := make([]byte, 8)
+
+	binary.LittleEndian.PutUint64(buf, uint64(len(data)))
+	h.Write(buf)
+
+	for _, val := range data {
+		binary.LittleEndian.PutUint64(buf, uint64(val))
+		h.Write(buf)
+	}
+}
@@ -0,0 +1,234 @@
+package mRMR
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// ChiSquare returns the chi-square statistic of independence between a discretized feature and the class.
+func ChiSquare(feature []float64, class []int) float64 {
+	stat, _ := ChiSquareTest(feature, class)
+	return stat
+}
+
+// ChiSquareTest returns the chi-square statistic of the contingency table of feature values and classes,
+// and its p-value with (values-1)(classes-1) degrees of freedom.
+func ChiSquareTest(feature []float64, class []int) (float64, float64) {
+	if len(feature) != len(class) {
+		panic("data and class slices must have the same length")
+	}
+
+	n := float64(len(feature))
+	joint := make(map[[2]float64]int)
+	totals := make(map[float64]int)
+	for i, val := range feature {
+		joint[[2]float64{val, float64(class[i])}]++
+		totals[val]++
+	}
+	counts := classCounts(class)
+
+	values := sortedFloatKeys(totals)
+	classes := sortedKeys(counts)
+
+	stat := 0.0
+	for _, val := range values {
+		for _, c := range classes {
+			expected := float64(totals[val]) * float64(counts[c]) / n
+			diff := float64(joint[[2]float64{val, float64(c)}]) - expected
+			stat += diff * diff / expected
+		}
+	}
+
+	df := float64((len(values) - 1) * (len(classes) - 1))
+	if df == 0 {
+		return 0, 1
+	}
+
+	return stat, chiSquareSurvival(stat, df)
+}
+
+// KruskalWallis returns the Kruskal-Wallis H statistic of a feature across the classes.
+func KruskalWallis(feature []float64, class []int) float64 {
+	stat, _ := KruskalWallisTest(feature, class)
+	return stat
+}
+
+// KruskalWallisTest returns the tie-corrected Kruskal-Wallis H statistic and its chi-square p-value
+// with classes-1 degrees of freedom.
+func KruskalWallisTest(feature []float64, class []int) (float64, float64) {
+	if len(feature) != len(class) {
+		panic("data and class slices must have the same length")
+	}
+
+	n := float64(len(feature))
+	ranks := rank(feature)
+
+	rankSum := make(map[int]float64)
+	for i, c := range class {
+		rankSum[c] += ranks[i]
+	}
+	counts := classCounts(class)
+
+	h := 0.0
+	for _, c := range sortedKeys(counts) {
+		h += rankSum[c] * rankSum[c] / float64(counts[c])
+	}
+	h = 12/(n*(n+1))*h - 3*(n+1)
+
+	// correct for ties
+	ties := 0.0
+	for _, t := range valueCounts(feature) {
+		ties += float64(t*t*t - t)
+	}
+	if correction := 1 - ties/(n*n*n-n); correction > 0 {
+		h /= correction
+	} else {
+		return 0, 1
+	}
+
+	return h, chiSquareSurvival(h, float64(len(counts)-1))
+}
+
+// FTest returns the one-way ANOVA f-statistic of a feature across the classes and its p-value.
+func FTest(feature []float64, class []int) (float64, float64) {
+	f := FStatistic(feature, class)
+	k := float64(uniqueClass(class))
+
+	return f, fSurvival(f, k-1, float64(len(feature))-k)
+}
+
+// AUC returns the area under the ROC curve of a feature for separating two classes,
+// taken in whichever direction separates them better, so it is in [0.5, 1].
+func AUC(feature []float64, class []int) float64 {
+	auc, _ := AUCTest(feature, class)
+	return math.Max(auc, 1-auc)
+}
+
+// AUCTest returns the area under the ROC curve of a feature, with the larger class label as positive,
+// and the two-sided p-value of the Mann-Whitney U test by its tie-corrected normal approximation.
+func AUCTest(feature []float64, class []int) (float64, float64) {
+	if len(feature) != len(class) {
+		panic("data and class slices must have the same length")
+	}
+
+	negative, positive := twoClasses(class)
+
+	ranks := rank(feature)
+	n0, n1 := 0.0, 0.0
+	rankSum := 0.0
+	for i, c := range class {
+		if c == positive {
+			rankSum += ranks[i]
+			n1++
+		} else if c == negative {
+			n0++
+		}
+	}
+
+	u := rankSum - n1*(n1+1)/2
+	auc := u / (n0 * n1)
+
+	n := n0 + n1
+	ties := 0.0
+	for _, t := range valueCounts(feature) {
+		ties += float64(t*t*t - t)
+	}
+	variance := n0 * n1 / 12 * ((n + 1) - ties/(n*(n-1)))
+	if variance <= 0 {
+		return auc, 1
+	}
+
+	return auc, normalTwoSided((u - n0*n1/2) / math.Sqrt(variance))
+}
+
+// WelchT returns the absolute Welch t-statistic of a feature between two classes.
+func WelchT(feature []float64, class []int) float64 {
+	t, _ := WelchTTest(feature, class)
+	return math.Abs(t)
+}
+
+// WelchTTest returns the Welch t-statistic of the larger class label against the smaller one,
+// and its two-sided p-value with Welch-Satterthwaite degrees of freedom.
+// A class with a single member has no variance to test against, so the result is 0 with p-value 1.
+func WelchTTest(feature []float64, class []int) (float64, float64) {
+	if len(feature) != len(class) {
+		panic("data and class slices must have the same length")
+	}
+
+	negative, positive := twoClasses(class)
+
+	var groups [2][]float64
+	for i, c := range class {
+		if c == positive {
+			groups[1] = append(groups[1], feature[i])
+		} else if c == negative {
+			groups[0] = append(groups[0], feature[i])
+		}
+	}
+
+	if len(groups[0]) < 2 || len(groups[1]) < 2 {
+		return 0, 1
+	}
+
+	var means, vars, sizes [2]float64
+	for g, data := range groups {
+		sizes[g] = float64(len(data))
+		means[g] = mean(data)
+		for _, val := range data {
+			vars[g] += (val - means[g]) * (val - means[g])
+		}
+		vars[g] /= sizes[g] - 1
+	}
+
+	se0, se1 := vars[0]/sizes[0], vars[1]/sizes[1]
+	if se0+se1 == 0 {
+		return 0, 1
+	}
+
+	t := (means[1] - means[0]) / math.Sqrt(se0+se1)
+	df := (se0 + se1) * (se0 + se1) / (se0*se0/(sizes[0]-1) + se1*se1/(sizes[1]-1))
+
+	return t, tTwoSided(t, df)
+}
+
+// PValueRelevance turns a test returning a statistic and p-value into a relevance function scoring -log10(p),
+// which puts tests with different statistics on one scale. p-values below the smallest float64 score about 323.
+func PValueRelevance(test func([]float64, []int) (float64, float64)) func([]float64, []int) float64 {
+	return func(feature []float64, class []int) float64 {
+		_, p := test(feature, class)
+		return -math.Log10(math.Max(p, math.SmallestNonzeroFloat64))
+	}
+}
+
+// twoClasses returns the two class labels in ascending order, and panics if there are not exactly two.
+func twoClasses(class []int) (int, int) {
+	labels := sortedKeys(classCounts(class))
+	if len(labels) != 2 {
+		panic(fmt.Sprintf("two classes required, got %d", len(labels)))
+	}
+
+	return labels[0], labels[1]
+}
+
+// valueCounts returns the number of occurrences of each value.
+func valueCounts(data []float64) map[float64]int {
+	counts := make(map[float64]int)
+
+	for _, val := range data {
+		counts[val]++
+	}
+
+	return counts
+}
+
+func sortedFloatKeys[V any](m map[float64]V) []float64 {
+	keys := make([]float64, 0, len(m))
+
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Float64s(keys)
+
+	return keys
+}
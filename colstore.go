@@ -0,0 +1,365 @@
+package mRMR
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+)
+
+// Column store layout, all values little-endian:
+//
+//	magic   [8]byte  "MRMRCOL1"
+//	rows    uint64
+//	cols    uint64
+//	class   [rows]int64
+//	data    [cols][rows]float64   (column-major)
+const (
+	storeMagic      = "MRMRCOL1"
+	storeHeaderSize = 24
+	storeBlockBytes = 64 << 20 // rows buffered per write pass are capped by this size
+)
+
+// ColumnStore is a memory-mapped, column-major dataset on disk, written by ConvertCSV.
+// Features are decoded one column at a time, so the dataset never has to fit in memory.
+type ColumnStore struct {
+	rows  int
+	cols  int
+	class []int
+	data  []byte
+}
+
+// ConvertCSV streams a CSV file (one instance per row, one feature per column) into a column store at storePath.
+// groupIndex is the 1-based column holding the class labels; if header is true the first row holds feature names,
+// which are returned.
+func ConvertCSV(csvPath, storePath string, groupIndex int, header bool) []string {
+	groupIndex -= 1
+
+	var features []string
+	var class []int
+	groupMap := make(map[string]int)
+	rows, cols := 0, -1
+
+	// First pass: count instances and collect class labels
+	readRecords(csvPath, func(i int, record []string) {
+		if groupIndex < 0 || groupIndex >= len(record) {
+			panic(fmt.Sprintf("groupIndex %d out of range in row %d", groupIndex, i))
+		}
+
+		if cols == -1 {
+			cols = len(record) - 1
+		}
+
+		if header && i == 0 {
+			features = append(append(features, record[:groupIndex]...), record[groupIndex+1:]...)
+			return
+		}
+
+		groupStr := record[groupIndex]
+		if groupStr == "NA" {
+			panic(fmt.Sprintf("NA encountered at groupIndex %d in row %d", groupIndex, i))
+		}
+
+		if _, exists := groupMap[groupStr]; !exists {
+			groupMap[groupStr] = len(groupMap)
+		}
+		class = append(class, groupMap[groupStr])
+		rows++
+	})
+
+	if rows == 0 || cols <= 0 {
+		panic(fmt.Sprintf("no data found in %s", csvPath))
+	}
+
+	file, err := os.Create(storePath)
+	if err != nil {
+		panic(fmt.Sprintf("unable to create file %s: %v", storePath, err))
+	}
+	defer file.Close()
+
+	head := make([]byte, storeHeaderSize+8*rows)
+	copy(head, storeMagic)
+	binary.LittleEndian.PutUint64(head[8:], uint64(rows))
+	binary.LittleEndian.PutUint64(head[16:], uint64(cols))
+	for i, c := range class {
+		binary.LittleEndian.PutUint64(head[storeHeaderSize+8*i:], uint64(c))
+	}
+
+	if _, err := file.Write(head); err != nil {
+		panic(fmt.Sprintf("error writing %s: %v", storePath, err))
+	}
+
+	dataStart := int64(len(head))
+	if err := file.Truncate(dataStart + int64(8*rows*cols)); err != nil {
+		panic(fmt.Sprintf("error writing %s: %v", storePath, err))
+	}
+
+	// Second pass: buffer a block of rows, then write each column's segment of the block
+	blockRows := storeBlockBytes / (8 * cols)
+	if blockRows < 1 {
+		blockRows = 1
+	}
+
+	block := make([]byte, 8*blockRows*cols)
+	first, filled := 0, 0
+
+	flush := func() {
+		for j := 0; j < cols; j++ {
+			segment := block[8*j*blockRows : 8*(j*blockRows+filled)]
+			offset := dataStart + int64(8*(j*rows+first))
+
+			if _, err := file.WriteAt(segment, offset); err != nil {
+				panic(fmt.Sprintf("error writing %s: %v", storePath, err))
+			}
+		}
+
+		first += filled
+		filled = 0
+	}
+
+	readRecords(csvPath, func(i int, record []string) {
+		if header && i == 0 {
+			return
+		}
+
+		if len(record)-1 != cols {
+			panic(fmt.Sprintf("row %d has %d features, expected %d", i, len(record)-1, cols))
+		}
+
+		j := 0
+		for k, field := range record {
+			if k == groupIndex {
+				continue
+			}
+
+			if field == "NA" {
+				panic(fmt.Sprintf("NA encountered at row %d, column %d", i, k))
+			}
+			num, err := strconv.ParseFloat(field, 64)
+			if err != nil {
+				panic(fmt.Sprintf("invalid float at row %d, column %d: %v", i, k, err))
+			}
+
+			binary.LittleEndian.PutUint64(block[8*(j*blockRows+filled):], math.Float64bits(num))
+			j++
+		}
+
+		filled++
+		if filled == blockRows {
+			flush()
+		}
+	})
+
+	if filled > 0 {
+		flush()
+	}
+
+	return features
+}
+
+// readRecords calls fn with each record of a CSV file, streaming it from disk.
+func readRecords(csvPath string, fn func(int, []string)) {
+	file, err := os.Open(csvPath)
+	if err != nil {
+		panic(fmt.Sprintf("unable to open file %s: %v", csvPath, err))
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(bufio.NewReader(file))
+	reader.ReuseRecord = true
+
+	for i := 0; ; i++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			panic(fmt.Sprintf("error reading CSV data: %v", err))
+		}
+
+		fn(i, record)
+	}
+}
+
+// OpenColumnStore memory-maps a column store written by ConvertCSV.
+func OpenColumnStore(storePath string) *ColumnStore {
+	file, err := os.Open(storePath)
+	if err != nil {
+		panic(fmt.Sprintf("unable to open file %s: %v", storePath, err))
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		panic(fmt.Sprintf("unable to stat file %s: %v", storePath, err))
+	}
+
+	data, err := mapFile(file, int(info.Size()))
+	if err != nil {
+		panic(fmt.Sprintf("unable to map file %s: %v", storePath, err))
+	}
+
+	if len(data) < storeHeaderSize || string(data[:8]) != storeMagic {
+		unmapFile(data)
+		panic(fmt.Sprintf("%s is not a column store", storePath))
+	}
+
+	rows := int(binary.LittleEndian.Uint64(data[8:]))
+	cols := int(binary.LittleEndian.Uint64(data[16:]))
+
+	if len(data) != storeHeaderSize+8*rows+8*rows*cols {
+		unmapFile(data)
+		panic(fmt.Sprintf("%s is truncated", storePath))
+	}
+
+	class := make([]int, rows)
+	for i := range class {
+		class[i] = int(int64(binary.LittleEndian.Uint64(data[storeHeaderSize+8*i:])))
+	}
+
+	return &ColumnStore{rows: rows, cols: cols, class: class, data: data}
+}
+
+// Close unmaps the store. Columns must not be read afterwards.
+func (s *ColumnStore) Close() error {
+	data := s.data
+	s.data = nil
+
+	return unmapFile(data)
+}
+
+// Rows returns the number of instances.
+func (s *ColumnStore) Rows() int {
+	return s.rows
+}
+
+// Cols returns the number of features.
+func (s *ColumnStore) Cols() int {
+	return s.cols
+}
+
+// Class returns the class labels stored with the data.
+func (s *ColumnStore) Class() []int {
+	return s.class
+}
+
+// Column decodes feature j into buf, which is grown if needed, and returns it.
+func (s *ColumnStore) Column(j int, buf []float64) []float64 {
+	if j < 0 || j >= s.cols {
+		panic(fmt.Sprintf("index %d out of range", j))
+	}
+
+	if cap(buf) < s.rows {
+		buf = make([]float64, s.rows)
+	}
+	buf = buf[:s.rows]
+
+	offset := storeHeaderSize + 8*s.rows + 8*j*s.rows
+	for i := range buf {
+		buf[i] = math.Float64frombits(binary.LittleEndian.Uint64(s.data[offset+8*i:]))
+	}
+
+	return buf
+}
+
+// storeFeatures returns the column store as a feature set, discretizing or quantizing columns as MRMR would.
+func (paras *ParasmRMR) storeFeatures() *storeSet {
+	return &storeSet{
+		s:              paras.Data.Store,
+		prepare:        paras.prepare(),
+		relevanceFunc:  paras.RelevanceFunc,
+		redundancyFunc: paras.RedundancyFunc,
+		workers:        paras.Workers,
+		targets:        make(map[int][]float64),
+		lazy:           paras.Prune == "lazy",
+	}
+}
+
+// prepare returns the preprocessing of a single column for data read one column at a time, or nil if there is none.
+// The column is binned in place.
+func (paras *ParasmRMR) prepare() func([]float64) []float64 {
+	if paras.spec().Preprocess == "quantize" {
+		return func(col []float64) []float64 {
+			return binColumn(col, paras.QLevel, true)
+		}
+	}
+
+	if paras.Discretization {
+		return func(col []float64) []float64 {
+			return binColumn(col, paras.BinSize, false)
+		}
+	}
+
+	return nil
+}
+
+// storeSet serves features from a column store, loading and preparing each column into a buffer of the worker
+// that scores it. Exact selection scores only against the newest selected feature, so only its prepared column
+// is kept; lazy pruning revisits older targets, so their columns are kept for the rest of the run.
+type storeSet struct {
+	s              *ColumnStore
+	prepare        func([]float64) []float64
+	relevanceFunc  func([]float64, []int) float64
+	redundancyFunc func([]float64, []float64) float64
+	workers        int
+	targets        map[int][]float64
+	lazy           bool
+}
+
+// column loads feature j into buf and prepares it.
+func (d *storeSet) column(j int, buf []float64) []float64 {
+	col := d.s.Column(j, buf)
+
+	if d.prepare != nil {
+		col = d.prepare(col)
+	}
+
+	return col
+}
+
+// target returns the prepared column of a selected feature, loading it on first use.
+func (d *storeSet) target(j int) []float64 {
+	if col, ok := d.targets[j]; ok {
+		return col
+	}
+
+	// outside lazy pruning the previous target is never scored against again, so its buffer is reused
+	var buf []float64
+	if !d.lazy {
+		for k, col := range d.targets {
+			buf = col
+			delete(d.targets, k)
+		}
+	}
+
+	col := d.column(j, buf)
+	d.targets[j] = col
+
+	return col
+}
+
+func (d *storeSet) numFeatures() int {
+	return d.s.cols
+}
+
+func (d *storeSet) relevance(ctx context.Context, class []int) ([]float64, error) {
+	bufs := buffers[float64](d.workers, d.s.rows)
+
+	return scoreEach(ctx, d.workers, d.s.cols, func(w, j int) float64 {
+		return d.relevanceFunc(d.column(j, bufs[w]), class)
+	})
+}
+
+func (d *storeSet) redundancy(ctx context.Context, candidates []int, target int) ([]float64, error) {
+	data2 := d.target(target)
+	bufs := buffers[float64](d.workers, d.s.rows)
+
+	return scoreEach(ctx, d.workers, len(candidates), func(w, i int) float64 {
+		return d.redundancyFunc(d.column(candidates[i], bufs[w]), data2)
+	})
+}
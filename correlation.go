@@ -0,0 +1,205 @@
+package mRMR
+
+import (
+	"math"
+	"sort"
+)
+
+// SpearmanCorrelation returns the absolute value of the Spearman rank correlation coefficient,
+// the pearson correlation of the ranks with ties given their average rank.
+func SpearmanCorrelation(data1, data2 []float64) float64 {
+	return spearmanCorrelation(data1, data2)
+}
+
+// spearmanCorrelation is SpearmanCorrelation of any Numeric type, computed in float64.
+func spearmanCorrelation[T Numeric](data1, data2 []T) float64 {
+	if len(data1) != len(data2) {
+		panic("feature slices must have the same length")
+	}
+
+	return PearsonCorrelation(rank(data1), rank(data2))
+}
+
+// KendallCorrelation returns the absolute value of Kendall's tau-b, which corrects for ties in either slice.
+// Discordant pairs are counted while merge sorting, in O(n log n) time.
+func KendallCorrelation(data1, data2 []float64) float64 {
+	return kendallCorrelation(data1, data2)
+}
+
+// kendallCorrelation is KendallCorrelation of any Numeric type, computed in float64.
+func kendallCorrelation[T Numeric](data1, data2 []T) float64 {
+	if len(data1) != len(data2) {
+		panic("feature slices must have the same length")
+	}
+
+	n := len(data1)
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.Slice(order, func(a, b int) bool {
+		i, j := order[a], order[b]
+		if data1[i] != data1[j] {
+			return data1[i] < data1[j]
+		}
+		return data2[i] < data2[j]
+	})
+
+	// pairs tied in data1, and tied in both
+	tiedX, tiedXY := 0, 0
+	runX, runXY := 1, 1
+	for k := 1; k <= n; k++ {
+		if k < n && data1[order[k]] == data1[order[k-1]] {
+			runX++
+			if data2[order[k]] == data2[order[k-1]] {
+				runXY++
+			} else {
+				tiedXY += runXY * (runXY - 1) / 2
+				runXY = 1
+			}
+			continue
+		}
+
+		tiedX += runX * (runX - 1) / 2
+		tiedXY += runXY * (runXY - 1) / 2
+		runX, runXY = 1, 1
+	}
+
+	y := make([]T, n)
+	for k, i := range order {
+		y[k] = data2[i]
+	}
+	swaps := mergeCount(y, make([]T, n))
+
+	// pairs tied in data2, now sorted
+	tiedY := 0
+	runY := 1
+	for k := 1; k <= n; k++ {
+		if k < n && y[k] == y[k-1] {
+			runY++
+			continue
+		}
+
+		tiedY += runY * (runY - 1) / 2
+		runY = 1
+	}
+
+	pairs := n * (n - 1) / 2
+	numerator := float64(pairs - tiedX - tiedY + tiedXY - 2*swaps)
+	denominator := math.Sqrt(float64(pairs-tiedX) * float64(pairs-tiedY))
+
+	return math.Abs(numerator / denominator)
+}
+
+// DistanceCorrelation returns the distance correlation of two slices, which is 0 only if they are independent
+// and also detects non-monotone dependence. It takes O(n^2) time and O(n) memory.
+func DistanceCorrelation(data1, data2 []float64) float64 {
+	return distanceCorrelation(data1, data2)
+}
+
+// distanceCorrelation is DistanceCorrelation of any Numeric type, computed in float64.
+func distanceCorrelation[T Numeric](data1, data2 []T) float64 {
+	if len(data1) != len(data2) {
+		panic("feature slices must have the same length")
+	}
+
+	rowMean1, mean1 := distanceMeans(data1)
+	rowMean2, mean2 := distanceMeans(data2)
+
+	// double-centred distances are rebuilt pair by pair instead of being stored
+	cov, var1, var2 := 0.0, 0.0, 0.0
+	for i := range data1 {
+		for j := range data1 {
+			a := math.Abs(float64(data1[i])-float64(data1[j])) - rowMean1[i] - rowMean1[j] + mean1
+			b := math.Abs(float64(data2[i])-float64(data2[j])) - rowMean2[i] - rowMean2[j] + mean2
+
+			cov += a * b
+			var1 += a * a
+			var2 += b * b
+		}
+	}
+
+	if var1 <= 0 || var2 <= 0 {
+		return 0
+	}
+
+	// cov can be slightly negative from rounding
+	return math.Sqrt(math.Max(cov, 0) / math.Sqrt(var1*var2))
+}
+
+// rank returns the rank of each value, starting from 1, with ties given their average rank.
+func rank[T Numeric](data []T) []float64 {
+	order := make([]int, len(data))
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.Slice(order, func(a, b int) bool {
+		return data[order[a]] < data[order[b]]
+	})
+
+	r := make([]float64, len(data))
+	for start := 0; start < len(order); {
+		end := start + 1
+		for end < len(order) && data[order[end]] == data[order[start]] {
+			end++
+		}
+
+		avg := float64(start+end+1) / 2
+		for _, i := range order[start:end] {
+			r[i] = avg
+		}
+
+		start = end
+	}
+
+	return r
+}
+
+// mergeCount sorts data in place and returns the number of swaps of adjacent elements a bubble sort would make.
+func mergeCount[T Numeric](data, buf []T) int {
+	if len(data) < 2 {
+		return 0
+	}
+
+	mid := len(data) / 2
+	swaps := mergeCount(data[:mid], buf[:mid]) + mergeCount(data[mid:], buf[mid:])
+
+	i, j, k := 0, mid, 0
+	for i < mid && j < len(data) {
+		if data[j] < data[i] {
+			buf[k] = data[j]
+			swaps += mid - i
+			j++
+		} else {
+			buf[k] = data[i]
+			i++
+		}
+		k++
+	}
+	k += copy(buf[k:], data[i:mid])
+	copy(buf[k:], data[j:])
+	copy(data, buf[:len(data)])
+
+	return swaps
+}
+
+// distanceMeans returns the mean absolute distance from each value to all others, and the overall mean.
+func distanceMeans[T Numeric](data []T) ([]float64, float64) {
+	n := float64(len(data))
+	rowMean := make([]float64, len(data))
+	total := 0.0
+
+	for i, x := range data {
+		sum := 0.0
+		for _, y := range data {
+			sum += math.Abs(float64(x) - float64(y))
+		}
+
+		rowMean[i] = sum / n
+		total += sum
+	}
+
+	return rowMean, total / (n * n)
+}
@@ -0,0 +1,66 @@
+package mRMR
+
+import "fmt"
+
+// CostAdjustment penalizes mRMR scores by the acquisition cost of each feature.
+// mode "penalty" returns score - lambda*cost, mode "ratio" returns score per unit cost.
+func CostAdjustment(score, costs []float64, lambda float64, mode string) []float64 {
+	if len(score) != len(costs) {
+		panic("Fail to adjust score by cost: Unequal length of data")
+	}
+
+	const epsilon = 1e-8
+	r := make([]float64, len(score))
+
+	for i, val := range score {
+		switch mode {
+		case "penalty":
+			r[i] = val - lambda*costs[i]
+		case "ratio":
+			divisor := costs[i]
+			if divisor == 0 {
+				divisor = epsilon //avoid division by zero
+			}
+
+			r[i] = val / divisor
+		default:
+			panic("Invalid cost mode. Choose from 'penalty' or 'ratio'")
+		}
+	}
+
+	return r
+}
+
+// affordable returns the candidates whose cost fits in the remaining budget.
+func affordable(candidates []int, costs []float64, remaining float64) []int {
+	r := make([]int, 0, len(candidates))
+
+	for _, f := range candidates {
+		if costs[f] <= remaining {
+			r = append(r, f)
+		}
+	}
+
+	return r
+}
+
+// checkCosts panics if the costs do not describe every feature.
+func checkCosts(costs []float64, numFeatures int) {
+	if err := validateCosts(costs, numFeatures); err != nil {
+		panic(err.Error())
+	}
+}
+
+func validateCosts(costs []float64, numFeatures int) error {
+	if len(costs) != numFeatures {
+		return fmt.Errorf("costs has %d entries, expected one per feature (%d)", len(costs), numFeatures)
+	}
+
+	for i, c := range costs {
+		if c < 0 {
+			return fmt.Errorf("cost of feature %d is negative: %v", i, c)
+		}
+	}
+
+	return nil
+}
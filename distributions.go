@@ -0,0 +1,150 @@
+package mRMR
+
+import "math"
+
+// chiSquareSurvival is P(X > x) for a chi-square variable with df degrees of freedom.
+func chiSquareSurvival(x, df float64) float64 {
+	if x <= 0 {
+		return 1
+	}
+
+	return gammaQ(df/2, x/2)
+}
+
+// fSurvival is P(X > f) for an F variable with d1 and d2 degrees of freedom.
+func fSurvival(f, d1, d2 float64) float64 {
+	if f <= 0 {
+		return 1
+	}
+
+	return betaI(d2/2, d1/2, d2/(d2+d1*f))
+}
+
+// tTwoSided is P(|X| > |t|) for a Student t variable with df degrees of freedom.
+func tTwoSided(t, df float64) float64 {
+	return betaI(df/2, 0.5, df/(df+t*t))
+}
+
+// normalTwoSided is P(|X| > |z|) for a standard normal variable.
+func normalTwoSided(z float64) float64 {
+	return math.Erfc(math.Abs(z) / math.Sqrt2)
+}
+
+// gammaQ is the regularized upper incomplete gamma function Q(a, x),
+// by its series for x < a+1 and its continued fraction otherwise.
+func gammaQ(a, x float64) float64 {
+	lgamma, _ := math.Lgamma(a)
+	prefix := math.Exp(-x + a*math.Log(x) - lgamma)
+
+	if x < a+1 {
+		sum, term := 1/a, 1/a
+		for n := 1; n < 1000; n++ {
+			term *= x / (a + float64(n))
+			sum += term
+			if math.Abs(term) < math.Abs(sum)*1e-16 {
+				break
+			}
+		}
+
+		return 1 - sum*prefix
+	}
+
+	// modified Lentz's method
+	const tiny = 1e-300
+	b := x + 1 - a
+	c := 1 / tiny
+	d := 1 / b
+	h := d
+	for n := 1; n < 1000; n++ {
+		an := -float64(n) * (float64(n) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < 1e-16 {
+			break
+		}
+	}
+
+	return prefix * h
+}
+
+// betaI is the regularized incomplete beta function I_x(a, b).
+func betaI(a, b, x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+
+	lga, _ := math.Lgamma(a)
+	lgb, _ := math.Lgamma(b)
+	lgab, _ := math.Lgamma(a + b)
+	front := math.Exp(lgab - lga - lgb + a*math.Log(x) + b*math.Log(1-x))
+
+	// the continued fraction converges quickly for x below the mean
+	if x < (a+1)/(a+b+2) {
+		return front * betaFraction(a, b, x) / a
+	}
+
+	return 1 - front*betaFraction(b, a, 1-x)/b
+}
+
+// betaFraction evaluates the continued fraction of the incomplete beta function by modified Lentz's method.
+func betaFraction(a, b, x float64) float64 {
+	const tiny = 1e-300
+
+	c := 1.0
+	d := 1 - (a+b)*x/(a+1)
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	h := d
+
+	for m := 1; m < 1000; m++ {
+		fm := float64(m)
+
+		// even step
+		num := fm * (b - fm) * x / ((a + 2*fm - 1) * (a + 2*fm))
+		d = 1 + num*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + num/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		h *= d * c
+
+		// odd step
+		num = -(a + fm) * (a + b + fm) * x / ((a + 2*fm) * (a + 2*fm + 1))
+		d = 1 + num*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + num/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+
+		if math.Abs(delta-1) < 1e-16 {
+			break
+		}
+	}
+
+	return h
+}
@@ -0,0 +1,233 @@
+package mRMR
+
+import (
+	"math"
+	"sort"
+)
+
+// nsbGrid is the number of points over log(beta) at which the NSB integrals are evaluated.
+const nsbGrid = 400
+
+// Entropy estimates the entropy in bits of a discrete data slice:
+// "plugin" (or "") uses the observed frequencies, "miller-madow" adds the (m-1)/2n bias correction,
+// "chao-shen" corrects for unseen values by the sample coverage, "james-stein" shrinks the frequencies
+// towards uniform, and "nsb" averages Dirichlet posteriors under a prior that is flat in entropy.
+// The alphabet is taken to be the observed values.
+func Entropy[T Numeric](data []T, estimator string) float64 {
+	count := histogram(data)
+
+	return estimateCounts(count, float64(len(data)), len(count), estimator)
+}
+
+// MutualInfoEstimate is MutualInfo with entropies from an Entropy estimator. The joint alphabet is
+// every pair of observed values. Bias-corrected estimates can be slightly negative for independent data.
+func MutualInfoEstimate[T1, T2 Numeric](data1 []T1, data2 []T2, estimator string) float64 {
+	ha, hb, hab := mutualEntropies(data1, data2, estimator)
+
+	return ha + hb - hab
+}
+
+// mutualEntropies returns the estimated entropies of two data slices and their joint entropy.
+func mutualEntropies[T1, T2 Numeric](data1 []T1, data2 []T2, estimator string) (float64, float64, float64) {
+	if len(data1) != len(data2) {
+		panic("Fail to calculate joint entropy: Unequal length of data")
+	}
+
+	n := float64(len(data1))
+	count1 := histogram(data1)
+	count2 := histogram(data2)
+
+	joint := make(map[[2]float64]int)
+	for i, val := range data1 {
+		joint[[2]float64{float64(val), float64(data2[i])}]++
+	}
+
+	return estimateCounts(count1, n, len(count1), estimator),
+		estimateCounts(count2, n, len(count2), estimator),
+		estimateCounts(joint, n, len(count1)*len(count2), estimator)
+}
+
+func histogram[T Numeric](data []T) map[float64]int {
+	count := make(map[float64]int)
+
+	for _, val := range data {
+		count[float64(val)]++
+	}
+
+	return count
+}
+
+// estimateCounts estimates the entropy of a histogram over n samples with an alphabet of bins values,
+// at least as many as observed. Counts are visited in a fixed order.
+func estimateCounts[K comparable](count map[K]int, n float64, bins int, estimator string) float64 {
+	counts := make([]int, 0, len(count))
+	for _, val := range count {
+		counts = append(counts, val)
+	}
+	sort.Ints(counts)
+
+	switch estimator {
+	case "", "plugin":
+		return pluginEntropy(counts, n)
+	case "miller-madow":
+		return pluginEntropy(counts, n) + float64(len(counts)-1)/(2*n*math.Ln2)
+	case "chao-shen":
+		return chaoShenEntropy(counts, n)
+	case "james-stein":
+		return jamesSteinEntropy(counts, n, bins)
+	case "nsb":
+		return nsbEntropy(counts, n, bins)
+	default:
+		panic("Invalid estimator. Choose from 'plugin', 'miller-madow', 'chao-shen', 'james-stein' or 'nsb'")
+	}
+}
+
+func pluginEntropy(counts []int, n float64) float64 {
+	sum := 0.0
+
+	for _, val := range counts {
+		prob := float64(val) / n
+		sum += prob * math.Log2(prob)
+	}
+
+	return -sum
+}
+
+// chaoShenEntropy is the Horvitz-Thompson entropy estimate with coverage-adjusted frequencies.
+func chaoShenEntropy(counts []int, n float64) float64 {
+	singletons := 0
+	for _, val := range counts {
+		if val == 1 {
+			singletons++
+		}
+	}
+
+	// a sample of only singletons would have zero coverage
+	if float64(singletons) == n {
+		singletons--
+	}
+	coverage := 1 - float64(singletons)/n
+
+	sum := 0.0
+	for _, val := range counts {
+		prob := coverage * float64(val) / n
+		sum += prob * math.Log2(prob) / (1 - math.Pow(1-prob, n))
+	}
+
+	return -sum
+}
+
+// jamesSteinEntropy is the entropy of the frequencies shrunk towards the uniform distribution over bins values,
+// with the shrinkage intensity of Hausser and Strimmer (2009).
+func jamesSteinEntropy(counts []int, n float64, bins int) float64 {
+	if n <= 1 {
+		return pluginEntropy(counts, n)
+	}
+
+	target := 1 / float64(bins)
+	unseen := float64(bins - len(counts))
+
+	sumSq, distance := 0.0, unseen*target*target
+	for _, val := range counts {
+		prob := float64(val) / n
+		sumSq += prob * prob
+		distance += (target - prob) * (target - prob)
+	}
+
+	lambda := 1.0
+	if distance > 0 {
+		lambda = math.Max(0, math.Min(1, (1-sumSq)/((n-1)*distance)))
+	}
+
+	sum := 0.0
+	for _, val := range counts {
+		prob := lambda*target + (1-lambda)*float64(val)/n
+		sum += prob * math.Log2(prob)
+	}
+	if prob := lambda * target; prob > 0 {
+		sum += unseen * prob * math.Log2(prob)
+	}
+
+	return -sum
+}
+
+// nsbEntropy is the posterior mean entropy under a mixture of symmetric Dirichlet priors over bins values,
+// with concentrations beta weighted so that the prior on entropy is flat (Nemenman, Shafee and Bialek, 2002).
+// The mixture is integrated numerically over log(beta).
+func nsbEntropy(counts []int, n float64, bins int) float64 {
+	if bins < 2 {
+		return 0
+	}
+
+	k := float64(bins)
+	unseen := k - float64(len(counts))
+
+	logWeights := make([]float64, nsbGrid)
+	means := make([]float64, nsbGrid)
+	maxLog := math.Inf(-1)
+
+	const low, high = -10.0, 10.0
+	step := (high - low) / float64(nsbGrid-1)
+
+	for g := range logWeights {
+		beta := math.Exp(low + float64(g)*step)
+		a := k * beta
+
+		// log evidence of the counts under Dirichlet(beta)
+		lgA, _ := math.Lgamma(a)
+		lgAN, _ := math.Lgamma(a + n)
+		lgB, _ := math.Lgamma(beta)
+		evidence := lgA - lgAN
+		for _, val := range counts {
+			lg, _ := math.Lgamma(float64(val) + beta)
+			evidence += lg - lgB
+		}
+
+		// prior density of beta, d/dbeta of the prior mean entropy, times beta for integrating over log(beta)
+		prior := (k*trigamma(a+1) - trigamma(beta+1)) * beta
+
+		logWeights[g] = evidence + math.Log(prior)
+		maxLog = math.Max(maxLog, logWeights[g])
+
+		// posterior mean entropy in nats
+		mean := digamma(a + n + 1)
+		for _, val := range counts {
+			mean -= (float64(val) + beta) / (a + n) * digamma(float64(val)+beta+1)
+		}
+		mean -= unseen * beta / (a + n) * digamma(beta+1)
+		means[g] = mean
+	}
+
+	num, den := 0.0, 0.0
+	for g, lw := range logWeights {
+		w := math.Exp(lw - maxLog)
+		num += w * means[g]
+		den += w
+	}
+
+	return num / den / math.Ln2
+}
+
+// digamma is the derivative of log Gamma, by recurrence up to x >= 6 and its asymptotic series.
+func digamma(x float64) float64 {
+	r := 0.0
+	for x < 6 {
+		r -= 1 / x
+		x++
+	}
+
+	f := 1 / (x * x)
+	return r + math.Log(x) - 0.5/x - f*(1.0/12-f*(1.0/120-f*(1.0/252-f*(1.0/240-f/132))))
+}
+
+// trigamma is the derivative of digamma, by recurrence up to x >= 6 and its asymptotic series.
+func trigamma(x float64) float64 {
+	r := 0.0
+	for x < 6 {
+		r += 1 / (x * x)
+		x++
+	}
+
+	f := 1 / (x * x)
+	return r + 1/x + f/2 + f/x*(1.0/6-f*(1.0/30-f*(1.0/42-f/30)))
+}
@@ -0,0 +1,142 @@
+package mRMR
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// featureSet is the column access the selection loop needs from a dataset.
+// Scoring stops early with the context's error once ctx is done.
+type featureSet interface {
+	numFeatures() int
+
+	// relevance scores every feature against the class
+	relevance(ctx context.Context, class []int) ([]float64, error)
+
+	// redundancy scores every candidate against the target feature
+	redundancy(ctx context.Context, candidates []int, target int) ([]float64, error)
+}
+
+// denseSet serves features from a dense instance-by-feature matrix.
+type denseSet struct {
+	X              [][]float64
+	relevanceFunc  func([]float64, []int) float64
+	redundancyFunc func([]float64, []float64) float64
+	workers        int
+}
+
+func (d denseSet) numFeatures() int {
+	return len(d.X[0])
+}
+
+func (d denseSet) relevance(ctx context.Context, class []int) ([]float64, error) {
+	bufs := buffers[float64](d.workers, len(d.X))
+
+	return scoreEach(ctx, d.workers, len(d.X[0]), func(w, j int) float64 {
+		return d.relevanceFunc(getColInto(d.X, j, bufs[w]), class)
+	})
+}
+
+func (d denseSet) redundancy(ctx context.Context, candidates []int, target int) ([]float64, error) {
+	data2 := getCol(d.X, target)
+	bufs := buffers[float64](d.workers, len(d.X))
+
+	return scoreEach(ctx, d.workers, len(candidates), func(w, i int) float64 {
+		return d.redundancyFunc(getColInto(d.X, candidates[i], bufs[w]), data2)
+	})
+}
+
+// scoreEach returns score(w, i) for i in [0, n), stopping early if ctx is done, where w is the worker
+// scoring i. With more than one worker, indices are split between goroutines; each score is
+// computed independently, so the result does not depend on the number of workers.
+func scoreEach(ctx context.Context, workers, n int, score func(int, int) float64) ([]float64, error) {
+	r := make([]float64, n)
+
+	if workers <= 1 {
+		for i := range r {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			r[i] = score(0, i)
+		}
+
+		return r, nil
+	}
+
+	var wg sync.WaitGroup
+	var next atomic.Int64
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+
+			for ctx.Err() == nil {
+				i := int(next.Add(1) - 1)
+				if i >= n {
+					return
+				}
+
+				r[i] = score(w, i)
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// buffers returns a column buffer of n values for each worker of scoreEach, so that columns
+// are read without allocating. Measures must not keep the slices they are passed.
+func buffers[T any](workers, n int) [][]T {
+	bufs := make([][]T, max(workers, 1))
+	for w := range bufs {
+		bufs[w] = make([]T, n)
+	}
+
+	return bufs
+}
+
+// features returns the feature set MRMR selects from.
+func (paras *ParasmRMR) features() featureSet {
+	if paras.Data.Sparse != nil {
+		return newSparseSet(paras)
+	}
+
+	if paras.Data.Store != nil {
+		return paras.storeFeatures()
+	}
+
+	if paras.Data.Matrix != nil {
+		return paras.Data.Matrix.features(paras)
+	}
+
+	return denseSet{paras.Data.X, paras.RelevanceFunc, paras.RedundancyFunc, paras.Workers}
+}
+
+// dims returns the number of instances and features.
+func (data DatamRMR) dims() (int, int) {
+	if data.Sparse != nil {
+		return data.Sparse.Rows, data.Sparse.Cols
+	}
+
+	if data.Store != nil {
+		return data.Store.Rows(), data.Store.Cols()
+	}
+
+	if data.Matrix != nil {
+		return data.Matrix.Dims()
+	}
+
+	if len(data.X) == 0 {
+		return 0, 0
+	}
+
+	return len(data.X), len(data.X[0])
+}
@@ -0,0 +1,132 @@
+package mRMR
+
+import "fmt"
+
+// Flat is a Matrix in one contiguous backing slice. In row-major order instance i is
+// Data[i*Stride : i*Stride+Cols]; in column-major order feature j is Data[j*Stride : j*Stride+Rows],
+// so columns are views into Data and reading them allocates nothing. Stride 0 means the rows
+// or columns are packed back to back.
+type Flat[T Numeric] struct {
+	Data     []T
+	Rows     int
+	Cols     int
+	Stride   int
+	ColMajor bool
+}
+
+// NewFlat copies rows, one per instance, into a packed Flat in row- or column-major order.
+func NewFlat[T Numeric](rows [][]T, colMajor bool) Flat[T] {
+	m := Flat[T]{Rows: len(rows), ColMajor: colMajor}
+	if len(rows) > 0 {
+		m.Cols = len(rows[0])
+	}
+
+	m.Data = make([]T, m.Rows*m.Cols)
+	for i, row := range rows {
+		if len(row) != m.Cols {
+			panic(fmt.Sprintf("row %d has %d features, expected %d", i, len(row), m.Cols))
+		}
+
+		for j, val := range row {
+			m.Data[m.index(i, j)] = val
+		}
+	}
+
+	return m
+}
+
+// Dims returns the number of instances and features.
+func (m Flat[T]) Dims() (int, int) {
+	return m.Rows, m.Cols
+}
+
+// At returns the value of feature j for instance i.
+func (m Flat[T]) At(i, j int) T {
+	if i < 0 || i >= m.Rows || j < 0 || j >= m.Cols {
+		panic(fmt.Sprintf("index (%d, %d) out of range", i, j))
+	}
+
+	return m.Data[m.index(i, j)]
+}
+
+// Column returns feature j. In column-major order it is a view into Data that must not be modified;
+// in row-major order it is gathered into buf, which is grown if needed.
+func (m Flat[T]) Column(j int, buf []T) []T {
+	if j < 0 || j >= m.Cols {
+		panic(fmt.Sprintf("index %d out of range", j))
+	}
+
+	if m.ColMajor {
+		start := j * m.stride()
+		return m.Data[start : start+m.Rows : start+m.Rows]
+	}
+
+	if cap(buf) < m.Rows {
+		buf = make([]T, m.Rows)
+	}
+	buf = buf[:m.Rows]
+
+	stride := m.stride()
+	for i := range buf {
+		buf[i] = m.Data[i*stride+j]
+	}
+
+	return buf
+}
+
+// stride returns the distance between the starts of consecutive rows, or of consecutive columns in column-major order.
+func (m Flat[T]) stride() int {
+	if m.Stride != 0 {
+		return m.Stride
+	}
+
+	if m.ColMajor {
+		return m.Rows
+	}
+
+	return m.Cols
+}
+
+func (m Flat[T]) index(i, j int) int {
+	if m.ColMajor {
+		return j*m.stride() + i
+	}
+
+	return i*m.stride() + j
+}
+
+func (m Flat[T]) column(j int) []float64 {
+	col := make([]float64, m.Rows)
+
+	for i := range col {
+		col[i] = float64(m.Data[m.index(i, j)])
+	}
+
+	return col
+}
+
+func (m Flat[T]) validate() error {
+	if m.Rows < 0 || m.Cols < 0 || m.Stride < 0 {
+		return fmt.Errorf("Flat has negative dimensions: %d x %d with stride %d", m.Rows, m.Cols, m.Stride)
+	}
+
+	outer, inner := m.Rows, m.Cols
+	if m.ColMajor {
+		outer, inner = m.Cols, m.Rows
+	}
+
+	if m.Stride != 0 && m.Stride < inner {
+		return fmt.Errorf("Flat stride %d is shorter than %d values", m.Stride, inner)
+	}
+
+	if outer > 0 && len(m.Data) < (outer-1)*m.stride()+inner {
+		return fmt.Errorf("Flat data has %d values, too few for %d x %d with stride %d", len(m.Data), m.Rows, m.Cols, m.stride())
+	}
+
+	return nil
+}
+
+// features reads columns as views in column-major order, and into a buffer per worker in row-major order.
+func (m Flat[T]) features(paras *ParasmRMR) featureSet {
+	return newNativeSet(paras, m.Rows, m.Cols, m.Column)
+}
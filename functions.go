@@ -3,6 +3,7 @@ package mRMR
 import (
 	"fmt"
 	"math"
+	"sort"
 )
 
 func Discretization (data [][]float64, binSize int) ([][]float64, [][]float64) {
@@ -13,24 +14,6 @@ func Discretization (data [][]float64, binSize int) ([][]float64, [][]float64) {
 	r := len(data)
 	c := len(data[0])
 
-	min := make([]float64, c)
-	max := make([]float64, c)
-
-	for j := 0; j < c; j ++ {
-
-		min[j] = data[0][j]
-		max[j] = data[0][j]
-
-		for i := 0; i < r; i++ {
-			if data[i][j] > max[j] {
-				max[j] = data[i][j]
-			}
-			if data[i][j] < min[j] {
-				min[j] = data[i][j]
-			}
-		}
-	}
-
 	discreteData := make([][]float64, r)
 	quantizedData := make([][]float64, r)
 
@@ -40,22 +23,11 @@ func Discretization (data [][]float64, binSize int) ([][]float64, [][]float64) {
 	}
 
 	for j := 0; j < c; j++ {
-		
-		binWidth := (max[j] - min[j]) / float64(binSize)
+		discrete, quantized := discretizeColumn(getCol(data, j), binSize)
 
 		for i := 0; i < r; i++ {
-			binIdx := int(math.Floor((data[i][j] - min[j]) / binWidth))
-
-			if binIdx == binSize {
-				binIdx--
-			}
-
-			// replaced by bin indices
-			discreteData[i][j] = float64(binIdx)
-			
-			// replaced by midpoints of bins
-			binMidpoint := min[j] + (float64(binIdx)+0.5)*binWidth
-			quantizedData[i][j] = binMidpoint
+			discreteData[i][j] = discrete[i]
+			quantizedData[i][j] = quantized[i]
 		}
 	}
 
@@ -63,9 +35,93 @@ func Discretization (data [][]float64, binSize int) ([][]float64, [][]float64) {
 
 }
 
+// discretizeColumn bins a single feature into binSize equal-width bins and returns
+// the bin indices and the bin midpoints of each value.
+func discretizeColumn(col []float64, binSize int) ([]float64, []float64) {
+	min, binWidth := binRange(col, binSize)
+
+	discrete := make([]float64, len(col))
+	quantized := make([]float64, len(col))
+
+	for i, val := range col {
+		binIdx := binIndex(val, min, binWidth, binSize)
+
+		// replaced by bin indices
+		discrete[i] = float64(binIdx)
+
+		// replaced by midpoints of bins
+		quantized[i] = min + (float64(binIdx)+0.5)*binWidth
+	}
+
+	return discrete, quantized
+}
+
+// binColumn replaces the values of col by their bin indices, or by the bin midpoints if quantized,
+// as discretizeColumn gives them, and returns col.
+func binColumn(col []float64, binSize int, quantized bool) []float64 {
+	if len(col) == 0 {
+		return col
+	}
+
+	min, binWidth := binRange(col, binSize)
+
+	for i, val := range col {
+		binIdx := binIndex(val, min, binWidth, binSize)
+
+		if quantized {
+			col[i] = min + (float64(binIdx)+0.5)*binWidth
+		} else {
+			col[i] = float64(binIdx)
+		}
+	}
+
+	return col
+}
+
+// binRange returns the lower edge and the width of the binSize equal-width bins spanning col.
+func binRange(col []float64, binSize int) (float64, float64) {
+	min := col[0]
+	max := col[0]
+
+	for _, val := range col {
+		if val > max {
+			max = val
+		}
+		if val < min {
+			min = val
+		}
+	}
+
+	return min, (max - min) / float64(binSize)
+}
+
+// binIndex returns the bin of val, the maximum falling in the last bin. A constant column is one bin.
+func binIndex(val, min, binWidth float64, binSize int) int {
+	if binWidth == 0 {
+		return 0
+	}
+
+	binIdx := int(math.Floor((val - min) / binWidth))
+
+	if binIdx == binSize {
+		binIdx--
+	}
+
+	return binIdx
+}
+
 
-func getCol(data [][]float64, i int) []float64 {
-	col := make([]float64, len(data))
+// getColInto copies feature i of data into col, which holds one value per instance, and returns col.
+func getColInto[T any](data [][]T, i int, col []T) []T {
+	for n, val := range data {
+		col[n] = val[i]
+	}
+
+	return col
+}
+
+func getCol[T any](data [][]T, i int) []T {
+	col := make([]T, len(data))
 
 	for n, val := range data {
 		col[n] = val[i]
@@ -132,10 +188,12 @@ func getMaxIndex[T Numeric] (data []T) int {
 }
 
 
+// Delete returns a copy of data without the element at idx.
 func Delete[T any] (data []T, idx int) []T {
-	data = append(data[:idx], data[idx+1:]...)
+	r := make([]T, 0, len(data)-1)
+	r = append(r, data[:idx]...)
 
-	return data
+	return append(r, data[idx+1:]...)
 }
 
 func CheckIfAllNegative(data []float64) bool {
@@ -184,33 +242,57 @@ func MinMaxNormalization(data []float64) []float64 {
 
 // get the quantization level
 func QuantizationLevel(data [][]float64, threshold float64) int {
-	level := 2 
-
-	numFeatures := len(data[0])
-
-	for {
-		maxError := 0.0
-
-		_, quantizedData := Discretization(data, level)
+	column := func(j int) []float64 {
+		return getCol(data, j)
+	}
 
-		for i := 0; i < numFeatures; i++ {
-			quantizedFeature := getCol(quantizedData, i)
-			originalFeature := getCol(data, i)
+	return quantizationLevel(len(data[0]), column, threshold)
+}
 
-			QError := QuantizationError(quantizedFeature, originalFeature)
+// quantizationLevel finds the smallest level whose quantization error is within threshold
+// for every feature, reading one feature at a time through column. No feature can be within threshold
+// at a smaller level than the first it reaches on its own, so one pass over the features raises the level
+// to that bound, and further passes only check it, instead of one pass per level tried.
+func quantizationLevel(numFeatures int, column func(int) []float64, threshold float64) int {
+	level := 2
+
+	// stop at one bin per instance, since a threshold of 0 is rarely reached exactly
+	raise := func(col []float64) bool {
+		raised := false
+		for level < len(col) && quantizationError(col, level) > threshold {
+			level++
+			raised = true
+		}
+		return raised
+	}
 
-			if QError > maxError {
-				maxError = QError
-			}
+	for i := 0; i < numFeatures; i++ {
+		raise(column(i))
+	}
 
+	for {
+		raised := false
+		for i := 0; i < numFeatures && !raised; i++ {
+			raised = raise(column(i))
 		}
 
-		if maxError <= threshold {
+		if !raised {
 			return level
 		}
+	}
+}
+
+// quantizationError is the QuantizationError of col quantized at level, without building the quantized column.
+func quantizationError(col []float64, level int) float64 {
+	min, binWidth := binRange(col, level)
+	err := 0.0
 
-		level++
+	for _, val := range col {
+		quantized := min + (float64(binIndex(val, min, binWidth, level))+0.5)*binWidth
+		err += math.Pow(quantized - val, 2)
 	}
+
+	return err / float64(len(col))
 }
 
 // get the quantization error
@@ -226,11 +308,13 @@ func QuantizationError(quantizedData, originalData []float64) float64 {
 }
 
 func scaling(data []float64, factor float64) []float64 {
-	for i := range data {
-		data[i] /= factor
+	r := make([]float64, len(data))
+
+	for i, val := range data {
+		r[i] = val / factor
 	}
 
-	return data
+	return r
 }
 
 func uniqueClass(data []int) int {
@@ -253,13 +337,25 @@ func GetFeatures(features []string, indices []int) []string {
 	return selectedFeatures
 }
 
-func mean(lst []float64) float64 {
+func mean[T Numeric](lst []T) float64 {
 
 	a := 0.0
 
 	for _, val := range lst {
-		a += val
+		a += float64(val)
 	}
 
 	return a / float64(len(lst))
-}
\ No newline at end of file
+}
+
+// sortedKeys returns the keys of a map in ascending order.
+func sortedKeys[V any](m map[int]V) []int {
+	keys := make([]int, 0, len(m))
+
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Ints(keys)
+
+	return keys
+}
@@ -0,0 +1,142 @@
+package mRMR
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// groupMRMR selects whole groups, scoring each group by the joint variable of its members.
+// Selected groups are expanded into their member features, every member carries the relevance
+// of its group, and redundancy is keyed on the first member of each group.
+func (paras *ParasmRMR) groupMRMR(ctx context.Context, ck *checkpointer) ([]int, []float64, map[[2]int]float64, error) {
+	jointData, members := JointGroups(paras.Data.X, paras.Groups)
+
+	var groupCosts []float64
+	if paras.Costs != nil {
+		groupCosts = make([]float64, len(members))
+		for g, m := range members {
+			for _, f := range m {
+				groupCosts[g] += paras.Costs[f]
+			}
+		}
+	}
+
+	if paras.MaxFeatures > len(members) {
+		paras.MaxFeatures = len(members)
+	}
+
+	selectedGroups, groupRelevance, groupRedundancy, err := paras.selection(ctx, denseSet{jointData, paras.RelevanceFunc, paras.RedundancyFunc, paras.Workers}, groupCosts, ck)
+
+	if groupRelevance == nil {
+		return nil, nil, nil, err
+	}
+
+	selectedFeatures := make([]int, 0, len(selectedGroups))
+	for _, g := range selectedGroups {
+		selectedFeatures = append(selectedFeatures, members[g]...)
+	}
+
+	relevanceAll := make([]float64, len(paras.Data.X[0]))
+	for g, m := range members {
+		for _, f := range m {
+			relevanceAll[f] = groupRelevance[g]
+		}
+	}
+
+	redundancyMap := make(map[[2]int]float64, len(groupRedundancy))
+	for key, val := range groupRedundancy {
+		redundancyMap[[2]int{members[key[0]][0], members[key[1]][0]}] = val
+	}
+
+	return selectedFeatures, relevanceAll, redundancyMap, err
+}
+
+// JointGroups encodes each group of discrete features as a single joint variable.
+// It returns one column per group and the member features of each group, with groups ordered by label.
+func JointGroups(data [][]float64, groups []int) ([][]float64, [][]int) {
+	checkGroups(groups, len(data[0]))
+
+	byLabel := make(map[int][]int)
+	for f, g := range groups {
+		byLabel[g] = append(byLabel[g], f)
+	}
+
+	labels := make([]int, 0, len(byLabel))
+	for g := range byLabel {
+		labels = append(labels, g)
+	}
+	sort.Ints(labels)
+
+	members := make([][]int, len(labels))
+	for i, g := range labels {
+		members[i] = byLabel[g]
+	}
+
+	jointData := make([][]float64, len(data))
+	for i := range jointData {
+		jointData[i] = make([]float64, len(members))
+	}
+
+	for g, m := range members {
+		code := getCol(data, m[0])
+
+		for _, f := range m[1:] {
+			code = jointCode(code, getCol(data, f))
+		}
+
+		for i, val := range code {
+			jointData[i][g] = val
+		}
+	}
+
+	return jointData, members
+}
+
+// jointCode maps each distinct pair (data1[i], data2[i]) to a single code.
+func jointCode(data1, data2 []float64) []float64 {
+	codes := make(map[[2]float64]int)
+	r := make([]float64, len(data1))
+
+	for i, val := range data1 {
+		key := [2]float64{val, data2[i]}
+
+		code, exists := codes[key]
+		if !exists {
+			code = len(codes)
+			codes[key] = code
+		}
+
+		r[i] = float64(code)
+	}
+
+	return r
+}
+
+// dropGroup removes every member of group g from the candidates.
+func dropGroup(candidates []int, groups []int, g int) []int {
+	r := candidates[:0]
+
+	for _, f := range candidates {
+		if groups[f] != g {
+			r = append(r, f)
+		}
+	}
+
+	return r
+}
+
+// checkGroups panics if the group labels do not describe every feature.
+func checkGroups(groups []int, numFeatures int) {
+	if err := validateGroups(groups, numFeatures); err != nil {
+		panic(err.Error())
+	}
+}
+
+func validateGroups(groups []int, numFeatures int) error {
+	if len(groups) != numFeatures {
+		return fmt.Errorf("groups has %d entries, expected one per feature (%d)", len(groups), numFeatures)
+	}
+
+	return nil
+}
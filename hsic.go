@@ -0,0 +1,413 @@
+package mRMR
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// medianSample caps the number of values whose pairwise distances are used for the median heuristic.
+const medianSample = 1000
+
+// NormalizedHSIC returns the Hilbert-Schmidt independence criterion of two slices with Gaussian kernels,
+// normalized to [0, 1] by the HSIC of each slice with itself. Bandwidths follow the median heuristic.
+// It takes O(n^2) time and O(n) memory.
+func NormalizedHSIC(data1, data2 []float64) float64 {
+	if len(data1) != len(data2) {
+		panic("feature slices must have the same length")
+	}
+
+	return kernelAlignment(len(data1), gaussianKernel(data1), gaussianKernel(data2))
+}
+
+// NormalizedClassHSIC is NormalizedHSIC between a feature and the class, with a delta kernel for the class.
+func NormalizedClassHSIC(feature []float64, class []int) float64 {
+	if len(feature) != len(class) {
+		panic("data and class slices must have the same length")
+	}
+
+	delta := func(i, j int) float64 {
+		if class[i] == class[j] {
+			return 1
+		}
+		return 0
+	}
+
+	return kernelAlignment(len(feature), gaussianKernel(feature), delta)
+}
+
+// HSICRelevance returns normalized HSIC between a feature and the class as a relevance function.
+// approximation "" computes it exactly, "rff" through random Fourier features and "nystrom" through
+// a Nyström approximation, both with the given number of components and O(n*components) memory.
+// seed drives the random frequencies of "rff".
+func HSICRelevance(approximation string, components int, seed int64) func([]float64, []int) float64 {
+	if approximation == "" {
+		return NormalizedClassHSIC
+	}
+
+	featureMap := hsicFeatureMap(approximation, components, seed)
+
+	return func(feature []float64, class []int) float64 {
+		if len(feature) != len(class) {
+			panic("data and class slices must have the same length")
+		}
+
+		return mapAlignment(featureMap(feature), oneHot(class))
+	}
+}
+
+// HSICRedundancy returns normalized HSIC between two features as a redundancy function,
+// with the approximations of HSICRelevance.
+func HSICRedundancy(approximation string, components int, seed int64) func([]float64, []float64) float64 {
+	if approximation == "" {
+		return NormalizedHSIC
+	}
+
+	featureMap := hsicFeatureMap(approximation, components, seed)
+
+	return func(data1, data2 []float64) float64 {
+		if len(data1) != len(data2) {
+			panic("feature slices must have the same length")
+		}
+
+		return mapAlignment(featureMap(data1), featureMap(data2))
+	}
+}
+
+// MedianBandwidth returns the median distance between pairs of values, computed on at most
+// 1000 evenly spaced values. It falls back to 1 if that median is 0.
+func MedianBandwidth(data []float64) float64 {
+	step := 1
+	if len(data) > medianSample {
+		step = len(data) / medianSample
+	}
+
+	var sample []float64
+	for i := 0; i < len(data); i += step {
+		sample = append(sample, data[i])
+	}
+
+	dist := make([]float64, 0, len(sample)*(len(sample)-1)/2)
+	for i, x := range sample {
+		for _, y := range sample[i+1:] {
+			dist = append(dist, math.Abs(x-y))
+		}
+	}
+
+	if len(dist) == 0 {
+		return 1
+	}
+
+	sort.Float64s(dist)
+	median := dist[len(dist)/2]
+	if len(dist)%2 == 0 {
+		median = (dist[len(dist)/2-1] + median) / 2
+	}
+
+	if median == 0 {
+		return 1
+	}
+
+	return median
+}
+
+func hsicFeatureMap(approximation string, components int, seed int64) func([]float64) [][]float64 {
+	if components <= 0 {
+		panic("HSIC approximation needs a positive number of components")
+	}
+
+	switch approximation {
+	case "rff":
+		return func(data []float64) [][]float64 {
+			return fourierFeatures(data, components, seed)
+		}
+	case "nystrom":
+		return func(data []float64) [][]float64 {
+			return nystromFeatures(data, components)
+		}
+	default:
+		panic("Invalid HSIC approximation. Choose from '', 'rff' or 'nystrom'")
+	}
+}
+
+func gaussianKernel(data []float64) func(i, j int) float64 {
+	sigma := MedianBandwidth(data)
+	gamma := 1 / (2 * sigma * sigma)
+
+	return func(i, j int) float64 {
+		d := data[i] - data[j]
+		return math.Exp(-gamma * d * d)
+	}
+}
+
+// kernelAlignment is HSIC(K, L) / sqrt(HSIC(K, K) HSIC(L, L)) for n x n kernels given entry by entry.
+// Centred entries are rebuilt from row means instead of storing the matrices.
+func kernelAlignment(n int, k, l func(i, j int) float64) float64 {
+	rowMeanK, meanK := kernelMeans(n, k)
+	rowMeanL, meanL := kernelMeans(n, l)
+
+	kl, kk, ll := 0.0, 0.0, 0.0
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			a := k(i, j) - rowMeanK[i] - rowMeanK[j] + meanK
+			b := l(i, j) - rowMeanL[i] - rowMeanL[j] + meanL
+
+			kl += a * b
+			kk += a * a
+			ll += b * b
+		}
+	}
+
+	if kk <= 0 || ll <= 0 {
+		return 0
+	}
+
+	return math.Max(kl, 0) / math.Sqrt(kk*ll)
+}
+
+// kernelMeans returns the row means of a symmetric n x n kernel and its overall mean.
+func kernelMeans(n int, k func(i, j int) float64) ([]float64, float64) {
+	rowMean := make([]float64, n)
+	total := 0.0
+
+	for i := 0; i < n; i++ {
+		sum := 0.0
+		for j := 0; j < n; j++ {
+			sum += k(i, j)
+		}
+
+		rowMean[i] = sum / float64(n)
+		total += sum
+	}
+
+	return rowMean, total / float64(n*n)
+}
+
+// mapAlignment is kernelAlignment for kernels given by feature maps, one row of features per instance:
+// ||Φxᵀ Φy||² / (||Φxᵀ Φx|| ||Φyᵀ Φy||) with centred maps, in O(n*m^2) time.
+func mapAlignment(phi1, phi2 [][]float64) float64 {
+	center(phi1)
+	center(phi2)
+
+	kl := frobeniusSq(crossProduct(phi1, phi2))
+	kk := frobeniusSq(crossProduct(phi1, phi1))
+	ll := frobeniusSq(crossProduct(phi2, phi2))
+
+	if kk <= 0 || ll <= 0 {
+		return 0
+	}
+
+	return kl / math.Sqrt(kk*ll)
+}
+
+// fourierFeatures maps each value to random Fourier features of the Gaussian kernel with the median bandwidth.
+func fourierFeatures(data []float64, components int, seed int64) [][]float64 {
+	sigma := MedianBandwidth(data)
+	r := rand.New(rand.NewSource(seed))
+
+	freq := make([]float64, components)
+	phase := make([]float64, components)
+	for c := range freq {
+		freq[c] = r.NormFloat64() / sigma
+		phase[c] = r.Float64() * 2 * math.Pi
+	}
+
+	scale := math.Sqrt(2 / float64(components))
+	phi := make([][]float64, len(data))
+	for i, x := range data {
+		phi[i] = make([]float64, components)
+		for c := range freq {
+			phi[i][c] = scale * math.Cos(freq[c]*x+phase[c])
+		}
+	}
+
+	return phi
+}
+
+// nystromFeatures maps each value to K_nm U Λ^(-1/2), where K_mm = U Λ Uᵀ is the Gaussian kernel
+// on landmarks taken at evenly spaced quantiles of the data.
+func nystromFeatures(data []float64, components int) [][]float64 {
+	sigma := MedianBandwidth(data)
+	gamma := 1 / (2 * sigma * sigma)
+
+	sorted := append([]float64(nil), data...)
+	sort.Float64s(sorted)
+
+	if components > len(data) {
+		components = len(data)
+	}
+
+	landmarks := make([]float64, components)
+	for c := range landmarks {
+		landmarks[c] = sorted[(2*c+1)*len(sorted)/(2*components)]
+	}
+
+	kmm := make([][]float64, components)
+	for a := range kmm {
+		kmm[a] = make([]float64, components)
+		for b := range kmm[a] {
+			d := landmarks[a] - landmarks[b]
+			kmm[a][b] = math.Exp(-gamma * d * d)
+		}
+	}
+
+	values, vectors := symmetricEigen(kmm)
+
+	// drop directions the landmarks do not span, such as those of duplicate landmarks
+	maxValue := 0.0
+	for _, val := range values {
+		maxValue = math.Max(maxValue, val)
+	}
+
+	var keep []int
+	for c, val := range values {
+		if val > 1e-10*maxValue {
+			keep = append(keep, c)
+		}
+	}
+
+	phi := make([][]float64, len(data))
+	knm := make([]float64, components)
+	for i, x := range data {
+		for a, z := range landmarks {
+			d := x - z
+			knm[a] = math.Exp(-gamma * d * d)
+		}
+
+		phi[i] = make([]float64, len(keep))
+		for k, c := range keep {
+			sum := 0.0
+			for a := range knm {
+				sum += knm[a] * vectors[a][c]
+			}
+			phi[i][k] = sum / math.Sqrt(values[c])
+		}
+	}
+
+	return phi
+}
+
+// symmetricEigen returns the eigenvalues of a symmetric matrix and its eigenvectors as columns,
+// found by cyclic Jacobi rotations. The matrix is overwritten.
+func symmetricEigen(m [][]float64) ([]float64, [][]float64) {
+	n := len(m)
+
+	v := make([][]float64, n)
+	for i := range v {
+		v[i] = make([]float64, n)
+		v[i][i] = 1
+	}
+
+	for sweep := 0; sweep < 100; sweep++ {
+		off := 0.0
+		for p := 0; p < n; p++ {
+			for q := p + 1; q < n; q++ {
+				off += m[p][q] * m[p][q]
+			}
+		}
+		if off < 1e-22 {
+			break
+		}
+
+		for p := 0; p < n; p++ {
+			for q := p + 1; q < n; q++ {
+				if m[p][q] == 0 {
+					continue
+				}
+
+				theta := (m[q][q] - m[p][p]) / (2 * m[p][q])
+				t := math.Copysign(1, theta) / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+				c := 1 / math.Sqrt(t*t+1)
+				s := t * c
+
+				for k := 0; k < n; k++ {
+					mkp, mkq := m[k][p], m[k][q]
+					m[k][p] = c*mkp - s*mkq
+					m[k][q] = s*mkp + c*mkq
+				}
+				for k := 0; k < n; k++ {
+					mpk, mqk := m[p][k], m[q][k]
+					m[p][k] = c*mpk - s*mqk
+					m[q][k] = s*mpk + c*mqk
+				}
+				for k := 0; k < n; k++ {
+					vkp, vkq := v[k][p], v[k][q]
+					v[k][p] = c*vkp - s*vkq
+					v[k][q] = s*vkp + c*vkq
+				}
+			}
+		}
+	}
+
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = m[i][i]
+	}
+
+	return values, v
+}
+
+// oneHot is the feature map of the delta kernel on the class.
+func oneHot(class []int) [][]float64 {
+	index := make(map[int]int)
+	for _, c := range sortedKeys(classCounts(class)) {
+		index[c] = len(index)
+	}
+
+	phi := make([][]float64, len(class))
+	for i, c := range class {
+		phi[i] = make([]float64, len(index))
+		phi[i][index[c]] = 1
+	}
+
+	return phi
+}
+
+// center subtracts the mean of each column in place.
+func center(phi [][]float64) {
+	if len(phi) == 0 {
+		return
+	}
+
+	for c := range phi[0] {
+		sum := 0.0
+		for _, row := range phi {
+			sum += row[c]
+		}
+
+		mean := sum / float64(len(phi))
+		for _, row := range phi {
+			row[c] -= mean
+		}
+	}
+}
+
+// crossProduct returns aᵀ b for matrices with one row per instance.
+func crossProduct(a, b [][]float64) [][]float64 {
+	r := make([][]float64, len(a[0]))
+	for p := range r {
+		r[p] = make([]float64, len(b[0]))
+	}
+
+	for i := range a {
+		for p, x := range a[i] {
+			for q, y := range b[i] {
+				r[p][q] += x * y
+			}
+		}
+	}
+
+	return r
+}
+
+func frobeniusSq(m [][]float64) float64 {
+	sum := 0.0
+
+	for _, row := range m {
+		for _, val := range row {
+			sum += val * val
+		}
+	}
+
+	return sum
+}
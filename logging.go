@@ -0,0 +1,39 @@
+package mRMR
+
+import (
+	"context"
+	"log/slog"
+	"os"
+)
+
+// logger returns the logger for a run: Logger if set, a debug-level logger on stdout if Verbose,
+// and otherwise the default logger restricted to warnings.
+func (paras *ParasmRMR) logger() *slog.Logger {
+	if paras.Logger != nil {
+		return paras.Logger
+	}
+
+	if paras.Verbose {
+		return slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	}
+
+	return slog.New(levelHandler{slog.Default().Handler(), slog.LevelWarn})
+}
+
+// levelHandler drops records below a minimum level before they reach the wrapped handler.
+type levelHandler struct {
+	slog.Handler
+	level slog.Level
+}
+
+func (h levelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level && h.Handler.Enabled(ctx, level)
+}
+
+func (h levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return levelHandler{h.Handler.WithAttrs(attrs), h.level}
+}
+
+func (h levelHandler) WithGroup(name string) slog.Handler {
+	return levelHandler{h.Handler.WithGroup(name), h.level}
+}
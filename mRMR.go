@@ -1,10 +1,12 @@
 package mRMR
 
 import (
+	"context"
 	"math"
-	"strings"
 	"fmt"
-	"log"
+	"log/slog"
+	"strings"
+	"time"
 )
 
 type Numeric interface{
@@ -16,15 +18,48 @@ type ParasmRMR struct{
 	Data				DatamRMR
 	Discretization		bool 
 	BinSize				int
-	Method 				string
-	Calculation 		string 
+	Method 				Method
+	Calculation 		Calculation
 	MaxFeatures			int	
-	RedundancyMethod 	string
+	RedundancyMethod 	RedundancyMethod
 	Threshold			float64
 	Verbose				bool
 	QLevel				int
 	RelevanceFunc		func ([]float64, []int) float64
 	RedundancyFunc  	func ([]float64, []float64) float64
+	Costs				[]float64	// acquisition cost of each feature
+	CostLambda			float64
+	CostMode			string
+	Budget				float64
+	Groups				[]int		// group label of each feature
+	GroupMode			string
+	MaxPerGroup			int
+	LabelAggregation	string
+	RedundancyMemory	int		// cap on redundancy values kept for the returned map, which is then partial; 0 keeps all
+	Timeout				time.Duration
+	Progress			func(ProgressEvent)
+	Logger				*slog.Logger
+	CheckpointPath		string
+	CheckpointEvery		int
+	TieBreak			string
+	Seed				int64
+	Workers				int		// goroutines scoring features, 0 or 1 runs serially
+	Relief				string	// "relieff" or "multisurf" replaces the relevance of Method
+	ReliefNeighbors		int
+	ReliefSamples		int		// instances scored by Relief, 0 scores all
+	MINormalization		string	// "su", "min", "sqrt" or "iqr" scales the mutual information of "mi-mi" and "nmi-nmi" into [0, 1]
+	Estimator			string	// entropy estimator of "mi-mi" and "nmi-nmi": "plugin", "miller-madow", "chao-shen", "james-stein" or "nsb"
+	Prune				string	// "top" keeps the PruneCandidates most relevant features, "lazy" skips candidates that cannot win
+	PruneCandidates		int
+	MICAlpha			float64	// exponent of the grid size n^alpha searched by "mic-mic"
+	MICClumps			float64	// clumps per column searched by "mic-mic"
+	HSICApproximation	string	// "rff" or "nystrom" approximates "hsic-hsic", which is exact if empty
+	HSICComponents		int		// number of components of the HSIC approximation
+
+	thresholdSet		bool	// Threshold was set by WithThreshold, so 0 is kept
+	methodRelevance		bool	// RelevanceFunc is the method's own, so a Matrix or sparse data can score with specialized measures
+	methodRedundancy	bool
+	normalized			bool	// the method's information measures are replaced by normalizedMeasures
 }
 
 // DatamRMR holds the input dataset and its class labels.
@@ -32,6 +67,10 @@ type ParasmRMR struct{
 type DatamRMR struct{
 	X 	[][]float64
 	Class []int
+	Labels [][]int	// optional label matrix for multi-label data, one row per instance
+	Sparse *SparseMatrix	// optional sparse replacement for X
+	Store *ColumnStore	// optional on-disk replacement for X
+	Matrix Matrix	// optional replacement for X in its own element type, such as Dense[float32]
 }
 
 // MRMR executes the mRMR feature selection and returns:
@@ -39,155 +78,302 @@ type DatamRMR struct{
 // - relevanceAll: the relevance scores of all features
 // - redundancyMap: a map storing pairwise redundancy values
 func (paras *ParasmRMR) MRMR() ([]int, []float64, map[[2]int]float64){
+	selectedFeatures, relevanceAll, redundancyMap, _ := paras.MRMRContext(context.Background())
+
+	return selectedFeatures, relevanceAll, redundancyMap
+}
+
+// MRMRContext is MRMR that stops once ctx is done or Timeout has passed.
+// Selection stops cleanly between steps: the features selected so far are returned with the context's error.
+func (paras *ParasmRMR) MRMRContext(ctx context.Context) ([]int, []float64, map[[2]int]float64, error){
+	ck := &checkpointer{path: paras.CheckpointPath}
+	if ck.path != "" {
+		ck.fingerprint = paras.fingerprint()
+	}
+
+	run := *paras
+
+	return run.run(ctx, ck)
+}
+
+// run executes a fresh or resumed selection, writing checkpoints through ck.
+// Defaults and preprocessed data are filled into paras, so it is called on a copy of the caller's parameters.
+func (paras *ParasmRMR) run(ctx context.Context, ck *checkpointer) ([]int, []float64, map[[2]int]float64, error){
+
+	if paras.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, paras.Timeout)
+		defer cancel()
+	}
 	
 	paras.defaults()
 	paras.setups()
 
-	if paras.Discretization && paras.Method != "nmi-nmi"{
+	// the checkpoint interval is known once defaults are filled in
+	ck.every = paras.CheckpointEvery
+
+	// Column stores are discretized one column at a time as they are read, matrices into bin codes up front
+	columnwise := paras.Data.Store != nil || paras.Data.Matrix != nil
+
+	if paras.Data.Matrix != nil {
+		if paras.spec().Preprocess == "quantize" {
+			paras.Data.Matrix = binMatrix(paras.Data.Matrix, paras.QLevel, true)
+		} else if paras.Discretization {
+			paras.Data.Matrix = binMatrix(paras.Data.Matrix, paras.BinSize, false)
+		}
+	}
+
+	if paras.Discretization && paras.Data.Sparse != nil && paras.spec().Preprocess == "" {
+		paras.Data.Sparse = DiscretizationSparse(paras.Data.Sparse, paras.BinSize)
+	} else if paras.Discretization && paras.spec().Preprocess == "" && !columnwise {
 		paras.Data.X, _ = Discretization(paras.Data.X, paras.BinSize)
 	}
 
-	if paras.Method == "nmi-nmi" {
+	if paras.spec().Preprocess == "quantize" && !columnwise {
 		_, paras.Data.X = Discretization(paras.Data.X, paras.QLevel)
 	}
 
-	relevanceAll := Relevance(paras.Data.X, paras.Data.Class, paras.RelevanceFunc)
+	if paras.GroupMode == "joint" {
+		return paras.groupMRMR(ctx, ck)
+	}
 
-	// Filter out features with zero relevance
-	featuresToConsider := make([]int, 0, len(paras.Data.X[0]))
-	for i, val := range relevanceAll {
-		if val > 0 {
-			featuresToConsider = append(featuresToConsider, i)
-		}
+	return paras.selection(ctx, paras.features(), paras.Costs, ck)
+}
+
+// defaults sets default parameter values for the mRMR procedure.
+func (paras *ParasmRMR) defaults() {
+	numInstances, numFeatures := paras.Data.dims()
+
+	if paras.Data.Store != nil && paras.Data.Class == nil && paras.Data.Labels == nil {
+		paras.Data.Class = paras.Data.Store.Class()
 	}
-	
-	if paras.Method == "fs-pearson" {    //fs
-		relevanceAll = MinMaxNormalization(relevanceAll)
+
+	if paras.BinSize == 0 {
+		paras.BinSize = int(math.Sqrt(float64(numInstances)))
 	}
 
-	if paras.Method == "nmi-nmi" {
-		n := uniqueClass(paras.Data.Class)
-		relevanceAll = scaling(relevanceAll, math.Log2(float64(n)))
+	if paras.Calculation == "" {
+		paras.Calculation = CalculationDiff
 	}
 
-	selectedFeatures := make([]int, 0, paras.MaxFeatures)
-	redundancyMap := make(map[[2]int]float64)
+	if paras.Method == "" {
+		paras.Method = MethodNMINMI
+	}
+	
+	if paras.RedundancyMethod == "" {
+		paras.RedundancyMethod = RedundancyAvg
+	}
 
-	for c := 0; c < paras.MaxFeatures; c++ {
+	if strings.EqualFold(string(paras.Method), string(MethodMICMIC)) {
+		if paras.MICAlpha == 0 {
+			paras.MICAlpha = 0.6
+		}
+		if paras.MICClumps == 0 {
+			paras.MICClumps = 15
+		}
+	}
 
-		relevance := selectByIndex(relevanceAll, featuresToConsider)
-		redundancy := make([]float64, len(featuresToConsider))
+	if paras.HSICApproximation != "" && paras.HSICComponents == 0 {
+		paras.HSICComponents = 100
+	}
 
-		if c != 0 {
-			// calculate redundancy
-			lastSelectedF := selectedFeatures[len(selectedFeatures) - 1]
+	if paras.MaxFeatures == 0 {
+		paras.MaxFeatures = numFeatures
+	}
 
-			// update map 
-			redundancyMap = RedundancyUpdate(paras.Data.X, featuresToConsider, lastSelectedF, redundancyMap, paras.RedundancyFunc)
+	if paras.Threshold == 0 && !paras.thresholdSet {
+		paras.Threshold = 0.01
+	}
 
-			for i, f := range featuresToConsider {
-				s := 0.0
-				for _, sel := range selectedFeatures {
-					val := redundancyMap[[2]int{sel, f}]
+	if paras.Costs != nil && paras.CostMode == "" {
+		paras.CostMode = "penalty"
+	}
 
-					if paras.RedundancyMethod == "avg"{
-						s += val
-					} else if paras.RedundancyMethod == "max" {
-						if val > s {
-							s = val
-						}
-					}
-				}
+	if paras.Budget > 0 && paras.Costs == nil {
+		panic("Budget requires Costs for every feature")
+	}
 
-				if paras.RedundancyMethod == "avg" {
-					divisor := float64(len(selectedFeatures))
-					if paras.Method == "nmi-nmi" {
-						divisor = math.Log2(float64(paras.QLevel))
-					} 
+	if paras.Costs != nil {
+		checkCosts(paras.Costs, numFeatures)
+	}
 
-					redundancy[i] = s / divisor
-				}
+	if paras.Data.Labels != nil {
+		checkLabels(paras.Data.Labels, numInstances)
 
-				if paras.RedundancyMethod == "max" {
-					redundancy[i] = s 
-				}
-				
-			}
-			
+		if paras.LabelAggregation == "" {
+			paras.LabelAggregation = "mean"
 		}
+	}
 
-		score := PairwiseOperation(relevance, redundancy, paras.Calculation)
-		
-		if paras.Verbose {
-			fmt.Println("relevance:", relevance)
-			fmt.Println("Redundancy:", redundancy)
-			fmt.Println(score, "\n")
-		}
+	if paras.GroupMode != "" {
+		checkGroups(paras.Groups, numFeatures)
+	}
 
-		// Early stopping
-		if (paras.Calculation == "diff" && CheckIfAllNegative(score)) ||
-			(paras.Calculation == "quo" && CheckIfAllSmallerOne(score)) {
-			break
-		}
+	if paras.GroupMode == "cap" && paras.MaxPerGroup == 0 {
+		paras.MaxPerGroup = 1
+	}
 
-		idx := getMaxIndex(score)
-		feature := featuresToConsider[idx]
-		selectedFeatures = append(selectedFeatures, feature)
-		featuresToConsider = Delete(featuresToConsider, idx)
+	if paras.TieBreak == "" {
+		paras.TieBreak = "lowest-index"
 	}
 
-	return selectedFeatures, relevanceAll, redundancyMap
+	if paras.Relief == "relieff" && paras.ReliefNeighbors == 0 {
+		paras.ReliefNeighbors = 10
+	}
+
+	if paras.CheckpointPath != "" && paras.CheckpointEvery == 0 {
+		paras.CheckpointEvery = 10
+	}
+
+	if paras.MaxFeatures > numFeatures {
+		paras.logger().Warn("maxFeatures exceeds number of features, adjusting",
+			"max_features", paras.MaxFeatures, "features", numFeatures)
+		paras.MaxFeatures = numFeatures
+	}
+
+	if paras.Prune == "top" && paras.PruneCandidates == 0 {
+		paras.PruneCandidates = 10 * paras.MaxFeatures
+	}
+
+	if paras.Prune == "top" && paras.PruneCandidates < paras.MaxFeatures {
+		paras.logger().Warn("pruneCandidates is below maxFeatures, adjusting",
+			"prune_candidates", paras.PruneCandidates, "max_features", paras.MaxFeatures)
+		paras.PruneCandidates = paras.MaxFeatures
+	}
 }
 
-// defaults sets default parameter values for the mRMR procedure.
-func (paras *ParasmRMR) defaults() {
-	if paras.BinSize == 0 {
-		paras.BinSize = int(math.Sqrt(float64(len(paras.Data.X))))
+// setups sets the parameters based on the selected method.
+// RelevanceFunc and RedundancyFunc supplied by the caller take precedence over the method's.
+func (paras *ParasmRMR) setups() {
+	spec := paras.spec()
+	relevanceFunc, redundancyFunc := spec.Relevance, spec.Redundancy
+
+	replace := false
+
+	if strings.EqualFold(string(paras.Method), string(MethodMICMIC)) {
+		if paras.MICAlpha <= 0 || paras.MICAlpha > 1 || paras.MICClumps <= 0 {
+			panic("MIC needs 0 < MICAlpha <= 1 and MICClumps > 0")
+		}
+		relevanceFunc, redundancyFunc = MICRelevance(paras.MICAlpha, paras.MICClumps), MICRedundancy(paras.MICAlpha, paras.MICClumps)
+	} else if paras.MICAlpha != 0 || paras.MICClumps != 0 {
+		panic(fmt.Sprintf("MICAlpha and MICClumps require method 'mic-mic', not %q", paras.Method))
 	}
 
-	if paras.Calculation == "" {
-		paras.Calculation = "diff"
+	switch paras.HSICApproximation {
+	case "":
+	case "rff", "nystrom":
+		if !strings.EqualFold(string(paras.Method), string(MethodHSICHSIC)) {
+			panic(fmt.Sprintf("HSICApproximation requires method 'hsic-hsic', not %q", paras.Method))
+		}
+		if paras.HSICComponents < 0 {
+			panic("HSIC approximation needs a positive number of components")
+		}
+		relevanceFunc = HSICRelevance(paras.HSICApproximation, paras.HSICComponents, paras.Seed)
+		redundancyFunc = HSICRedundancy(paras.HSICApproximation, paras.HSICComponents, paras.Seed)
+	default:
+		panic("Invalid HSIC approximation. Choose from 'rff' or 'nystrom'")
 	}
 
-	if paras.Method == "" {
-		paras.Method = "nmi-nmi"
+	switch paras.MINormalization {
+	case "":
+	case "su", "min", "sqrt", "iqr":
+		if !mutualInfoMethod(paras.Method) {
+			panic(fmt.Sprintf("MINormalization requires method 'mi-mi' or 'nmi-nmi', not %q", paras.Method))
+		}
+		replace = true
+	default:
+		panic("Invalid MI normalization. Choose from 'su', 'min', 'sqrt' or 'iqr'")
 	}
-	
-	if paras.RedundancyMethod == "" {
-		paras.RedundancyMethod = "avg"
+
+	switch paras.Estimator {
+	case "", "plugin":
+	case "miller-madow", "chao-shen", "james-stein", "nsb":
+		if !mutualInfoMethod(paras.Method) {
+			panic(fmt.Sprintf("Estimator requires method 'mi-mi' or 'nmi-nmi', not %q", paras.Method))
+		}
+		replace = true
+	default:
+		panic("Invalid estimator. Choose from 'plugin', 'miller-madow', 'chao-shen', 'james-stein' or 'nsb'")
 	}
 
-	if paras.MaxFeatures == 0 {
-		paras.MaxFeatures = len(paras.Data.X[0])
+	if replace {
+		relevanceFunc, redundancyFunc = paras.normalizedMeasures()
 	}
 
-	if paras.Threshold == 0{
-		paras.Threshold = 0.01
+	paras.normalized = replace
+
+	if paras.RelevanceFunc == nil {
+		paras.RelevanceFunc = relevanceFunc
+		paras.methodRelevance = true
 	}
 
-	if paras.MaxFeatures > len(paras.Data.X[0]) {
-		log.Printf("Warning: maxFeatures (%d) exceeds number of features (%d). Adjusting.",
-			paras.MaxFeatures, len(paras.Data.X[0]))
-		paras.MaxFeatures = len(paras.Data.X[0])
+	if paras.RedundancyFunc == nil {
+		paras.RedundancyFunc = redundancyFunc
+		paras.methodRedundancy = true
 	}
 
-}
+	if spec.Preprocess == "quantize" {
+		if paras.Data.Sparse != nil {
+			var buf []float64
+			column := func(j int) []float64 {
+				buf = paras.Data.Sparse.Col(j).dense(buf)
+				return buf
+			}
+			paras.QLevel = quantizationLevel(paras.Data.Sparse.Cols, column, paras.Threshold)
+		} else if paras.Data.Store != nil {
+			var buf []float64
+			column := func(j int) []float64 {
+				buf = paras.Data.Store.Column(j, buf)
+				return buf
+			}
+			paras.QLevel = quantizationLevel(paras.Data.Store.Cols(), column, paras.Threshold)
+		} else if paras.Data.Matrix != nil {
+			_, numFeatures := paras.Data.Matrix.Dims()
+			paras.QLevel = quantizationLevel(numFeatures, paras.Data.Matrix.column, paras.Threshold)
+		} else {
+			paras.QLevel = QuantizationLevel(paras.Data.X, paras.Threshold)
+		}
+	}
 
-// setups sets the parameters based on the selected method.
-func (paras *ParasmRMR) setups() {
-	
-	switch strings.ToLower(paras.Method) {
-	case "mi-mi":
-		paras.RelevanceFunc = MutualInfo
-		paras.RedundancyFunc = MutualInfo
-	case "fs-pearson":
-		paras.RelevanceFunc = FStatistic
-		paras.RedundancyFunc = PearsonCorrelation
-	case "nmi-nmi":
-		paras.RelevanceFunc = MutualInfo
-		paras.RedundancyFunc = MutualInfo
-		paras.QLevel = QuantizationLevel(paras.Data.X, paras.Threshold)
+	switch paras.TieBreak {
+	case "lowest-index", "relevance", "random":
+	default:
+		panic("Invalid tie break. Choose from 'lowest-index', 'relevance' or 'random'")
+	}
+
+	switch paras.Relief {
+	case "":
+	case "relieff", "multisurf":
+		if paras.Data.Sparse != nil || paras.Data.Store != nil || paras.Data.Matrix != nil {
+			panic("Relief requires dense data in X")
+		}
+		if paras.GroupMode == "joint" {
+			panic("Relief cannot score groups in GroupMode 'joint'")
+		}
+	default:
+		panic("Invalid relief. Choose from 'relieff' or 'multisurf'")
+	}
+
+	switch paras.Prune {
+	case "", "top":
+	case "lazy":
+		if !nonnegativeRedundancy(paras.Method, !paras.methodRedundancy, paras.Estimator, paras.MINormalization) {
+			panic("Prune 'lazy' requires the redundancy of a built-in method, with the plug-in estimator unless MINormalization is set")
+		}
+	default:
+		panic("Invalid pruning. Choose from 'top' or 'lazy'")
+	}
+
+	switch paras.GroupMode {
+	case "", "cap":
+	case "joint":
+		if paras.Data.Sparse != nil || paras.Data.Store != nil || paras.Data.Matrix != nil {
+			panic("GroupMode 'joint' requires dense data in X")
+		}
+		if !spec.Information {
+			panic("GroupMode 'joint' requires an information method such as 'mi-mi' or 'nmi-nmi'")
+		}
 	default:
-		panic("Invalid method. Choose from 'mi-mi', 'fs-pearson', 'nmi-nmi'")
+		panic("Invalid group mode. Choose from 'joint' or 'cap'")
 	}
 }
@@ -0,0 +1,238 @@
+package mRMR
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Matrix is a dense instance-by-feature matrix kept in its own element type, an alternative to X
+// for data that would double in memory as float64. Features are read one column at a time.
+type Matrix interface {
+	// Dims returns the number of instances and features.
+	Dims() (int, int)
+
+	// column returns feature j widened to float64.
+	column(j int) []float64
+
+	// validate reports a malformed matrix.
+	validate() error
+
+	// features returns the feature set MRMR selects from with paras.
+	features(paras *ParasmRMR) featureSet
+}
+
+// Dense is a Matrix of rows, one per instance, such as Dense[float32](rows) or Dense[int8](codes).
+type Dense[T Numeric] [][]T
+
+// Dims returns the number of instances and features.
+func (m Dense[T]) Dims() (int, int) {
+	if len(m) == 0 {
+		return 0, 0
+	}
+
+	return len(m), len(m[0])
+}
+
+func (m Dense[T]) column(j int) []float64 {
+	col := make([]float64, len(m))
+
+	for i, row := range m {
+		col[i] = float64(row[j])
+	}
+
+	return col
+}
+
+func (m Dense[T]) validate() error {
+	_, numFeatures := m.Dims()
+
+	for i, row := range m {
+		if len(row) != numFeatures {
+			return fmt.Errorf("Matrix row %d has %d features, expected %d", i, len(row), numFeatures)
+		}
+	}
+
+	return nil
+}
+
+func (m Dense[T]) features(paras *ParasmRMR) featureSet {
+	numInstances, numFeatures := m.Dims()
+
+	return newNativeSet(paras, numInstances, numFeatures, func(j int, buf []T) []T {
+		return getColInto(m, j, buf)
+	})
+}
+
+// codedMatrix is a Matrix binned once by Discretization or quantization, so that columns are not
+// binned again on every read. Bin indices are kept in the smallest integer type that holds them and
+// widen to exactly the values of discretizeColumn: the indices, or the bin midpoints if quantized.
+type codedMatrix[C Numeric] struct {
+	codes     Flat[C]
+	min       []float64
+	width     []float64
+	quantized bool
+}
+
+// binMatrix bins every column of m into binSize bins.
+func binMatrix(m Matrix, binSize int, quantized bool) Matrix {
+	switch {
+	case binSize <= math.MaxInt8+1:
+		return newCodedMatrix[int8](m, binSize, quantized)
+	case binSize <= math.MaxInt16+1:
+		return newCodedMatrix[int16](m, binSize, quantized)
+	default:
+		return newCodedMatrix[int32](m, binSize, quantized)
+	}
+}
+
+func newCodedMatrix[C Numeric](m Matrix, binSize int, quantized bool) codedMatrix[C] {
+	rows, cols := m.Dims()
+
+	c := codedMatrix[C]{
+		codes:     Flat[C]{Data: make([]C, rows*cols), Rows: rows, Cols: cols, ColMajor: true},
+		min:       make([]float64, cols),
+		width:     make([]float64, cols),
+		quantized: quantized,
+	}
+
+	for j := 0; j < cols; j++ {
+		col := m.column(j)
+		if len(col) == 0 {
+			continue
+		}
+
+		min, binWidth := binRange(col, binSize)
+		codes := c.codes.Column(j, nil)
+		for i, val := range col {
+			codes[i] = C(binIndex(val, min, binWidth, binSize))
+		}
+
+		c.min[j], c.width[j] = min, binWidth
+	}
+
+	return c
+}
+
+func (m codedMatrix[C]) Dims() (int, int) {
+	return m.codes.Dims()
+}
+
+func (m codedMatrix[C]) column(j int) []float64 {
+	return m.widen(j, make([]float64, m.codes.Rows))
+}
+
+// widen writes the bin indices or midpoints of feature j into col and returns it.
+func (m codedMatrix[C]) widen(j int, col []float64) []float64 {
+	for i, code := range m.codes.Column(j, nil) {
+		if m.quantized {
+			col[i] = m.min[j] + (float64(code)+0.5)*m.width[j]
+		} else {
+			col[i] = float64(code)
+		}
+	}
+
+	return col
+}
+
+func (m codedMatrix[C]) validate() error {
+	return nil
+}
+
+// features scores bin indices as codes, which the built-in measures treat exactly as the widened indices.
+// Midpoints are scored widened, as binning them again would lose nothing but time.
+func (m codedMatrix[C]) features(paras *ParasmRMR) featureSet {
+	if m.quantized {
+		return newNativeSet(paras, m.codes.Rows, m.codes.Cols, m.widen)
+	}
+
+	return m.codes.features(paras)
+}
+
+// nativeSet serves the features of a Matrix. The method's own measures score columns in their element type;
+// custom measures see each column widened to float64 as it is read.
+type nativeSet[T Numeric] struct {
+	rows           int
+	cols           int
+	column         func(int, []T) []T // feature j, read into a buffer of rows values unless it is a view
+	relevanceFunc  func([]T, []int) float64
+	redundancyFunc func([]T, []T) float64
+	workers        int
+}
+
+func newNativeSet[T Numeric](paras *ParasmRMR, rows, cols int, column func(int, []T) []T) nativeSet[T] {
+	d := nativeSet[T]{rows: rows, cols: cols, column: column, workers: paras.Workers}
+
+	widen := func(col []T) []float64 {
+		wide := make([]float64, len(col))
+		for i, val := range col {
+			wide[i] = float64(val)
+		}
+
+		return wide
+	}
+
+	relevance, redundancy, builtin := nativeMeasures[T](string(paras.Method))
+	builtin = builtin && !paras.normalized
+
+	if builtin && paras.methodRelevance {
+		d.relevanceFunc = relevance
+	} else {
+		relevanceFunc := paras.RelevanceFunc
+		d.relevanceFunc = func(col []T, class []int) float64 {
+			return relevanceFunc(widen(col), class)
+		}
+	}
+
+	if builtin && paras.methodRedundancy {
+		d.redundancyFunc = redundancy
+	} else {
+		redundancyFunc := paras.RedundancyFunc
+		d.redundancyFunc = func(data1, data2 []T) float64 {
+			return redundancyFunc(widen(data1), widen(data2))
+		}
+	}
+
+	return d
+}
+
+func (d nativeSet[T]) numFeatures() int {
+	return d.cols
+}
+
+func (d nativeSet[T]) relevance(ctx context.Context, class []int) ([]float64, error) {
+	bufs := buffers[T](d.workers, d.rows)
+
+	return scoreEach(ctx, d.workers, d.cols, func(w, j int) float64 {
+		return d.relevanceFunc(d.column(j, bufs[w]), class)
+	})
+}
+
+func (d nativeSet[T]) redundancy(ctx context.Context, candidates []int, target int) ([]float64, error) {
+	data2 := d.column(target, make([]T, d.rows))
+	bufs := buffers[T](d.workers, d.rows)
+
+	return scoreEach(ctx, d.workers, len(candidates), func(w, i int) float64 {
+		return d.redundancyFunc(d.column(candidates[i], bufs[w]), data2)
+	})
+}
+
+// nativeMeasures returns the measures of a built-in method for element type T.
+// They widen each value to float64 as they go, so they score exactly as on widened columns.
+func nativeMeasures[T Numeric](method string) (func([]T, []int) float64, func([]T, []T) float64, bool) {
+	switch strings.ToLower(method) {
+	case "mi-mi", "nmi-nmi":
+		return MutualInfo[T, int], MutualInfo[T, T], true
+	case "fs-pearson":
+		return fStatistic[T], pearsonCorrelation[T], true
+	case "fs-spearman":
+		return fStatistic[T], spearmanCorrelation[T], true
+	case "fs-kendall":
+		return fStatistic[T], kendallCorrelation[T], true
+	case "fs-dcor":
+		return fStatistic[T], distanceCorrelation[T], true
+	default:
+		return nil, nil, false
+	}
+}
@@ -0,0 +1,254 @@
+package mRMR
+
+import (
+	"math"
+	"sort"
+)
+
+// MIC returns the maximal information coefficient of two slices, approximated by the grid search
+// of Reshef et al. (2011): grids of x by y cells with x*y <= max(n^alpha, 4) are searched, one axis
+// equipartitioned and the other optimized over at most c*x clumps. MIC is in [0, 1] and symmetric.
+// Usual values are alpha = 0.6 and c = 15.
+func MIC(data1, data2 []float64, alpha, c float64) float64 {
+	if len(data1) != len(data2) {
+		panic("feature slices must have the same length")
+	}
+
+	if alpha <= 0 || alpha > 1 || c <= 0 {
+		panic("MIC needs 0 < alpha <= 1 and c > 0")
+	}
+
+	n := len(data1)
+	if n < 2 {
+		return 0
+	}
+
+	bound := math.Max(math.Pow(float64(n), alpha), 4)
+
+	return math.Max(micAxis(data1, data2, bound, c), micAxis(data2, data1, bound, c))
+}
+
+// MICRelevance returns MIC between a feature and the class as a relevance function.
+func MICRelevance(alpha, c float64) func([]float64, []int) float64 {
+	return func(feature []float64, class []int) float64 {
+		y := make([]float64, len(class))
+		for i, val := range class {
+			y[i] = float64(val)
+		}
+
+		return MIC(feature, y, alpha, c)
+	}
+}
+
+// MICRedundancy returns MIC between two features as a redundancy function.
+func MICRedundancy(alpha, c float64) func([]float64, []float64) float64 {
+	return func(data1, data2 []float64) float64 {
+		return MIC(data1, data2, alpha, c)
+	}
+}
+
+// micAxis is the largest normalized mutual information over grids whose rows equipartition y
+// and whose columns are optimized along x.
+func micAxis(x, y []float64, bound, c float64) float64 {
+	n := len(x)
+
+	byX := make([]int, n)
+	for i := range byX {
+		byX[i] = i
+	}
+	sort.SliceStable(byX, func(a, b int) bool { return x[byX[a]] < x[byX[b]] })
+
+	best := 0.0
+	for rows := 2; rows <= int(bound/2); rows++ {
+		maxCols := int(bound / float64(rows))
+		if maxCols < 2 {
+			break
+		}
+
+		q := equipartition(y, rows)
+		info := optimizeAxis(x, byX, q, maxCols, int(c*float64(maxCols)))
+
+		for cols := 2; cols <= maxCols; cols++ {
+			score := info[cols] / math.Log2(float64(min(cols, rows)))
+			if score > best {
+				best = score
+			}
+		}
+	}
+
+	return math.Min(best, 1)
+}
+
+// equipartition assigns each value to one of at most rows rows of about equal size,
+// keeping equal values in the same row.
+func equipartition(data []float64, rows int) []int {
+	n := len(data)
+
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return data[order[a]] < data[order[b]] })
+
+	q := make([]int, n)
+	row, size := 0, 0
+	desired := float64(n) / float64(rows)
+
+	for i := 0; i < n; {
+		j := i + 1
+		for j < n && data[order[j]] == data[order[i]] {
+			j++
+		}
+		run := j - i
+
+		// start a new row if adding the run moves the current one further from the desired size
+		if size != 0 && math.Abs(float64(size+run)-desired) >= math.Abs(float64(size)-desired) {
+			row++
+			size = 0
+			desired = float64(n-i) / float64(rows-row)
+		}
+
+		for _, k := range order[i:j] {
+			q[k] = row
+		}
+		size += run
+		i = j
+	}
+
+	return q
+}
+
+// optimizeAxis returns, for every number of columns l up to maxCols, the largest mutual information
+// between the rows q and a partition of the points sorted by x into at most l columns.
+// Columns are built from clumps, runs of points in the same row, merged down to at most maxClumps.
+func optimizeAxis(x []float64, byX []int, q []int, maxCols, maxClumps int) []float64 {
+	n := len(byX)
+
+	numRows := 0
+	for _, r := range q {
+		if r+1 > numRows {
+			numRows = r + 1
+		}
+	}
+
+	// clumps end where the row changes; points with equal x are never split,
+	// and a block of equal x spanning rows is a clump of its own
+	var ends []int
+	label, prevLabel := 0, -1
+	for i := 0; i < n; {
+		j := i + 1
+		mixed := false
+		for j < n && x[byX[j]] == x[byX[i]] {
+			if q[byX[j]] != q[byX[i]] {
+				mixed = true
+			}
+			j++
+		}
+
+		if mixed {
+			label = -1 - i
+		} else {
+			label = q[byX[i]]
+		}
+
+		if i > 0 && (label != prevLabel || label < 0) {
+			ends = append(ends, i)
+		}
+		prevLabel = label
+		i = j
+	}
+	ends = append(ends, n)
+
+	if len(ends) > maxClumps {
+		ends = superclumps(ends, maxClumps)
+	}
+
+	k := len(ends)
+	bounds := append([]int{0}, ends...)
+
+	// cumulative row counts at each clump boundary
+	cum := make([][]int, k+1)
+	cum[0] = make([]int, numRows)
+	for t := 1; t <= k; t++ {
+		cum[t] = append([]int(nil), cum[t-1]...)
+		for _, p := range byX[bounds[t-1]:bounds[t]] {
+			cum[t][q[p]]++
+		}
+	}
+
+	// cost of a column over clumps s+1..t: its share of points times the entropy of its rows
+	cost := func(s, t int) float64 {
+		size := float64(bounds[t] - bounds[s])
+		h := 0.0
+		for r := 0; r < numRows; r++ {
+			if count := cum[t][r] - cum[s][r]; count > 0 {
+				p := float64(count) / size
+				h -= p * math.Log2(p)
+			}
+		}
+		return size / float64(n) * h
+	}
+
+	costs := make([][]float64, k+1)
+	for s := 0; s < k; s++ {
+		costs[s] = make([]float64, k+1)
+		for t := s + 1; t <= k; t++ {
+			costs[s][t] = cost(s, t)
+		}
+	}
+
+	hq := cost(0, k)
+
+	// f[t] is the smallest conditional entropy of the rows given l columns over the first t clumps
+	f := make([]float64, k+1)
+	for t := 1; t <= k; t++ {
+		f[t] = costs[0][t]
+	}
+
+	info := make([]float64, maxCols+1)
+	for l := 2; l <= maxCols; l++ {
+		info[l] = info[l-1]
+		if l > k {
+			continue
+		}
+
+		next := make([]float64, k+1)
+		for t := l; t <= k; t++ {
+			next[t] = math.Inf(1)
+			for s := l - 1; s < t; s++ {
+				if val := f[s] + costs[s][t]; val < next[t] {
+					next[t] = val
+				}
+			}
+		}
+		f = next
+
+		if val := hq - f[k]; val > info[l] {
+			info[l] = val
+		}
+	}
+
+	return info
+}
+
+// superclumps merges consecutive clumps, given by their end positions, into at most maxClumps
+// groups of about equal size.
+func superclumps(ends []int, maxClumps int) []int {
+	n := ends[len(ends)-1]
+	merged := make([]int, 0, maxClumps)
+	desired := float64(n) / float64(maxClumps)
+	start, prev := 0, 0
+
+	for _, end := range ends {
+		size := prev - start
+		if size != 0 && math.Abs(float64(end-start)-desired) >= math.Abs(float64(size)-desired) &&
+			len(merged) < maxClumps-1 {
+			merged = append(merged, prev)
+			start = prev
+			desired = float64(n-start) / float64(maxClumps-len(merged))
+		}
+		prev = end
+	}
+
+	return append(merged, n)
+}
@@ -0,0 +1,23 @@
+//go:build !unix
+
+package mRMR
+
+import (
+	"io"
+	"os"
+)
+
+// mapFile reads the whole file where memory mapping is unavailable.
+func mapFile(file *os.File, size int) ([]byte, error) {
+	data := make([]byte, size)
+
+	if _, err := io.ReadFull(file, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func unmapFile(data []byte) error {
+	return nil
+}
@@ -0,0 +1,25 @@
+//go:build unix
+
+package mRMR
+
+import (
+	"os"
+	"syscall"
+)
+
+// mapFile maps a file read-only into memory.
+func mapFile(file *os.File, size int) ([]byte, error) {
+	if size == 0 {
+		return nil, nil
+	}
+
+	return syscall.Mmap(int(file.Fd()), 0, size, syscall.PROT_READ, syscall.MAP_SHARED)
+}
+
+func unmapFile(data []byte) error {
+	if data == nil {
+		return nil
+	}
+
+	return syscall.Munmap(data)
+}
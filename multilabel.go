@@ -0,0 +1,149 @@
+package mRMR
+
+import (
+	"context"
+	"fmt"
+	"math"
+)
+
+// relevance computes the relevance of each column of data to the class, or to the labels for multi-label data.
+func (paras *ParasmRMR) relevance(ctx context.Context, data featureSet) ([]float64, error) {
+	if paras.Data.Labels == nil {
+		return paras.classRelevance(ctx, data, paras.Data.Class)
+	}
+
+	if paras.LabelAggregation == "powerset" {
+		return paras.classRelevance(ctx, data, LabelPowerset(paras.Data.Labels))
+	}
+
+	labels := labelColumns(paras.Data.Labels)
+	perLabel := make([][]float64, len(labels))
+
+	for l, label := range labels {
+		relevance, err := paras.classRelevance(ctx, data, label)
+		if err != nil {
+			return nil, err
+		}
+		perLabel[l] = relevance
+	}
+
+	return AggregateRelevance(perLabel, paras.LabelAggregation), nil
+}
+
+// classRelevance computes the relevance to a single class vector, normalized for nmi-nmi.
+func (paras *ParasmRMR) classRelevance(ctx context.Context, data featureSet, class []int) ([]float64, error) {
+	var relevance []float64
+	var err error
+
+	if paras.Relief != "" {
+		relevance, err = paras.reliefRelevance(ctx, class)
+	} else {
+		relevance, err = data.relevance(ctx, class)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if paras.normalization() == "nmi" {
+		n := uniqueClass(class)
+		if n > 1 {
+			relevance = scaling(relevance, math.Log2(float64(n)))
+		}
+	}
+
+	return relevance, nil
+}
+
+// MultiLabelRelevance computes the relevance of each feature to every label column and aggregates it.
+func MultiLabelRelevance(data [][]float64, labels [][]int, relevanceFunc func([]float64, []int) float64, aggregation string) []float64 {
+	if aggregation == "powerset" {
+		return Relevance(data, LabelPowerset(labels), relevanceFunc)
+	}
+
+	cols := labelColumns(labels)
+	perLabel := make([][]float64, len(cols))
+
+	for l, label := range cols {
+		perLabel[l] = Relevance(data, label, relevanceFunc)
+	}
+
+	return AggregateRelevance(perLabel, aggregation)
+}
+
+// AggregateRelevance combines per-label relevance scores into one score per feature with "mean" or "max".
+func AggregateRelevance(perLabel [][]float64, aggregation string) []float64 {
+	r := make([]float64, len(perLabel[0]))
+
+	for l, relevance := range perLabel {
+		for i, val := range relevance {
+			switch aggregation {
+			case "mean":
+				r[i] += val / float64(len(perLabel))
+			case "max":
+				// starts from the first label, since relevance can be negative
+				if l == 0 || val > r[i] {
+					r[i] = val
+				}
+			default:
+				panic("Invalid label aggregation. Choose from 'mean', 'max' or 'powerset'")
+			}
+		}
+	}
+
+	return r
+}
+
+// LabelPowerset maps each distinct combination of labels to a single class.
+func LabelPowerset(labels [][]int) []int {
+	codes := make(map[string]int)
+	class := make([]int, len(labels))
+
+	for i, row := range labels {
+		key := fmt.Sprint(row)
+
+		code, exists := codes[key]
+		if !exists {
+			code = len(codes)
+			codes[key] = code
+		}
+
+		class[i] = code
+	}
+
+	return class
+}
+
+// labelColumns returns each label as a class vector.
+func labelColumns(labels [][]int) [][]int {
+	cols := make([][]int, len(labels[0]))
+
+	for l := range cols {
+		cols[l] = make([]int, len(labels))
+		for i, row := range labels {
+			cols[l][i] = row[l]
+		}
+	}
+
+	return cols
+}
+
+// checkLabels panics if the label matrix does not have one row per instance.
+func checkLabels(labels [][]int, numInstances int) {
+	if err := validateLabels(labels, numInstances); err != nil {
+		panic(err.Error())
+	}
+}
+
+func validateLabels(labels [][]int, numInstances int) error {
+	if len(labels) != numInstances {
+		return fmt.Errorf("labels has %d rows, expected one per instance (%d)", len(labels), numInstances)
+	}
+
+	for i, row := range labels {
+		if len(row) != len(labels[0]) {
+			return fmt.Errorf("labels row %d has %d labels, expected %d", i, len(row), len(labels[0]))
+		}
+	}
+
+	return nil
+}
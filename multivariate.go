@@ -0,0 +1,158 @@
+package mRMR
+
+import "sort"
+
+// JointEntropy returns the plug-in joint entropy in bits of any number of discrete data slices of equal length.
+// Each instance is coded by its tuple of values, so the cost is linear in the number of slices and instances.
+func JointEntropy[T Numeric](vars ...[]T) float64 {
+	if len(vars) == 0 {
+		return 0
+	}
+
+	codes, levels := codeValues(vars[0])
+	for _, data := range vars[1:] {
+		next, nextLevels := codeValues(data)
+		codes, levels = jointCodes(codes, next, levels, nextLevels)
+	}
+
+	return codeEntropy(codes, levels)
+}
+
+// ConditionalEntropy returns H(X|Z) = H(X,Z) - H(Z) in bits.
+func ConditionalEntropy[T1, T2 Numeric](x []T1, z []T2) float64 {
+	cx, lx := codeValues(x)
+	cz, lz := codeValues(z)
+	cxz, lxz := jointCodes(cx, cz, lx, lz)
+
+	return codeEntropy(cxz, lxz) - codeEntropy(cz, lz)
+}
+
+// ConditionalMutualInfo returns I(X;Y|Z) = H(X,Z) + H(Y,Z) - H(X,Y,Z) - H(Z) in bits.
+// Rounding can leave it slightly negative when X and Y are conditionally independent.
+func ConditionalMutualInfo[T1, T2, T3 Numeric](x []T1, y []T2, z []T3) float64 {
+	cx, lx := codeValues(x)
+	cy, ly := codeValues(y)
+	cz, lz := codeValues(z)
+
+	cxz, lxz := jointCodes(cx, cz, lx, lz)
+	cyz, lyz := jointCodes(cy, cz, ly, lz)
+	cxyz, lxyz := jointCodes(cxz, cy, lxz, ly)
+
+	return codeEntropy(cxz, lxz) + codeEntropy(cyz, lyz) - codeEntropy(cxyz, lxyz) - codeEntropy(cz, lz)
+}
+
+// InteractionInformation returns the interaction information of the data slices by inclusion-exclusion,
+// -sum over nonempty subsets S of (-1)^|S| H(S), in bits. With one slice it is its entropy, with two
+// their mutual information, and with three I(X;Y) - I(X;Y|Z): positive for redundancy, negative for synergy.
+// All 2^k subsets are visited, so k should be small.
+func InteractionInformation[T Numeric](vars ...[]T) float64 {
+	k := len(vars)
+	if k == 0 {
+		return 0
+	}
+	if k > 30 {
+		panic("Fail to calculate interaction information: Too many variables")
+	}
+
+	codes := make([][]int, k)
+	levels := make([]int, k)
+	for i, data := range vars {
+		codes[i], levels[i] = codeValues(data)
+	}
+
+	sum := 0.0
+	for subset := 1; subset < 1<<k; subset++ {
+		var joint []int
+		jointLevels, size := 0, 0
+
+		for i := 0; i < k; i++ {
+			if subset&(1<<i) == 0 {
+				continue
+			}
+			if size == 0 {
+				joint, jointLevels = codes[i], levels[i]
+			} else {
+				joint, jointLevels = jointCodes(joint, codes[i], jointLevels, levels[i])
+			}
+			size++
+		}
+
+		if size%2 == 1 {
+			sum += codeEntropy(joint, jointLevels)
+		} else {
+			sum -= codeEntropy(joint, jointLevels)
+		}
+	}
+
+	return sum
+}
+
+// codeValues codes the values of a data slice as 0, 1, ... in order of first appearance,
+// and returns the codes and the number of distinct values.
+func codeValues[T Numeric](data []T) ([]int, int) {
+	codes := make([]int, len(data))
+	seen := make(map[float64]int)
+
+	for i, val := range data {
+		code, ok := seen[float64(val)]
+		if !ok {
+			code = len(seen)
+			seen[float64(val)] = code
+		}
+		codes[i] = code
+	}
+
+	return codes, len(seen)
+}
+
+// jointCodes codes the pairs of two code slices, recoding densely so that codes never outgrow the instances.
+func jointCodes(a, b []int, levelsA, levelsB int) ([]int, int) {
+	if len(a) != len(b) {
+		panic("Fail to calculate joint entropy: Unequal length of data")
+	}
+
+	codes := make([]int, len(a))
+
+	// a dense table when the pair space is small, a map otherwise
+	if levelsA*levelsB <= 4*len(a)+64 {
+		table := make([]int, levelsA*levelsB)
+		levels := 0
+		for i := range a {
+			pair := a[i]*levelsB + b[i]
+			if table[pair] == 0 {
+				levels++
+				table[pair] = levels
+			}
+			codes[i] = table[pair] - 1
+		}
+		return codes, levels
+	}
+
+	seen := make(map[[2]int]int)
+	for i := range a {
+		pair := [2]int{a[i], b[i]}
+		code, ok := seen[pair]
+		if !ok {
+			code = len(seen)
+			seen[pair] = code
+		}
+		codes[i] = code
+	}
+
+	return codes, len(seen)
+}
+
+// codeEntropy is the plug-in entropy of dense codes, which are all observed, with counts summed in a fixed order.
+func codeEntropy(codes []int, levels int) float64 {
+	if len(codes) == 0 {
+		return 0
+	}
+
+	counts := make([]int, levels)
+	for _, code := range codes {
+		counts[code]++
+	}
+	sort.Ints(counts)
+
+	return pluginEntropy(counts, float64(len(codes)))
+}
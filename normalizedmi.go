@@ -0,0 +1,108 @@
+package mRMR
+
+import (
+	"math"
+	"strings"
+)
+
+// NormalizedMutualInfo scales the mutual information of two data slices into [0, 1]:
+// "su" is the symmetric uncertainty 2I/(H(A)+H(B)), "min" is I/min(H(A), H(B)),
+// "sqrt" is I/sqrt(H(A)H(B)) and "iqr" is the information quality ratio I/H(A,B).
+// It returns 0 if the denominator is 0, i.e. if either slice is constant.
+func NormalizedMutualInfo[T1, T2 Numeric](data1 []T1, data2 []T2, normalization string) float64 {
+	ha, hb, hab := mutualEntropies(data1, data2, "")
+
+	return normalizeMI(ha, hb, hab, normalization)
+}
+
+// SymmetricUncertainty is NormalizedMutualInfo with "su".
+func SymmetricUncertainty[T1, T2 Numeric](data1 []T1, data2 []T2) float64 {
+	return NormalizedMutualInfo(data1, data2, "su")
+}
+
+// SparseNormalizedMutualInfo is NormalizedMutualInfo for two sparse vectors.
+func SparseNormalizedMutualInfo(a, b SparseVector, normalization string) float64 {
+	ha, hb, hab := sparseEntropies(a, b, "")
+
+	return normalizeMI(ha, hb, hab, normalization)
+}
+
+// SparseNormalizedClassMutualInfo is NormalizedMutualInfo between a sparse vector and the class.
+func SparseNormalizedClassMutualInfo(a SparseVector, class []int, normalization string) float64 {
+	ha, hc, hac := sparseClassEntropies(a, class, classCounts(class), "")
+
+	return normalizeMI(ha, hc, hac, normalization)
+}
+
+// combineMI is the mutual information from entropies, normalized by normalization if set.
+func combineMI(ha, hb, hab float64, normalization string) float64 {
+	if normalization == "" {
+		return ha + hb - hab
+	}
+
+	return normalizeMI(ha, hb, hab, normalization)
+}
+
+func normalizeMI(ha, hb, hab float64, normalization string) float64 {
+	mi := ha + hb - hab
+
+	var divisor float64
+	switch normalization {
+	case "su":
+		divisor = (ha + hb) / 2
+	case "min":
+		divisor = math.Min(ha, hb)
+	case "sqrt":
+		divisor = math.Sqrt(ha * hb)
+	case "iqr":
+		divisor = hab
+	default:
+		panic("Invalid MI normalization. Choose from 'su', 'min', 'sqrt' or 'iqr'")
+	}
+
+	if divisor <= 0 {
+		return 0
+	}
+
+	// rounding can put the ratio just outside [0, 1]
+	return math.Min(math.Max(mi/divisor, 0), 1)
+}
+
+// normalizedMeasures returns the relevance and redundancy functions of MI from the Estimator,
+// normalized by MINormalization if set.
+func (paras *ParasmRMR) normalizedMeasures() (func([]float64, []int) float64, func([]float64, []float64) float64) {
+	normalization, estimator := paras.MINormalization, paras.Estimator
+
+	relevance := func(feature []float64, class []int) float64 {
+		ha, hc, hac := mutualEntropies(feature, class, estimator)
+		return combineMI(ha, hc, hac, normalization)
+	}
+	redundancy := func(data1, data2 []float64) float64 {
+		ha, hb, hab := mutualEntropies(data1, data2, estimator)
+		return combineMI(ha, hb, hab, normalization)
+	}
+
+	return relevance, redundancy
+}
+
+// mutualInfoMethod reports whether method is the built-in "mi-mi" or "nmi-nmi", whose measures
+// normalizedMeasures stands in for. Registered information methods keep their own measures.
+func mutualInfoMethod(method Method) bool {
+	switch Method(strings.ToLower(string(method))) {
+	case MethodMIMI, MethodNMINMI:
+		return true
+	default:
+		return false
+	}
+}
+
+// normalization is the normalization of the method, except that MINormalization replaces "nmi".
+func (paras *ParasmRMR) normalization() string {
+	normalization := paras.spec().Normalization
+
+	if normalization == "nmi" && paras.MINormalization != "" {
+		return ""
+	}
+
+	return normalization
+}
@@ -0,0 +1,453 @@
+package mRMR
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// Method names a relevance/redundancy method registered with RegisterMethod.
+type Method string
+
+const (
+	MethodMIMI       Method = "mi-mi"
+	MethodFSPearson  Method = "fs-pearson"
+	MethodNMINMI     Method = "nmi-nmi"
+	MethodFSSpearman Method = "fs-spearman"
+	MethodFSKendall  Method = "fs-kendall"
+	MethodFSDcor     Method = "fs-dcor"
+	MethodMICMIC     Method = "mic-mic"
+	MethodHSICHSIC   Method = "hsic-hsic"
+)
+
+// Calculation combines relevance and redundancy into the mRMR score.
+type Calculation string
+
+const (
+	CalculationDiff Calculation = "diff" // relevance - redundancy
+	CalculationQuo  Calculation = "quo"  // relevance / redundancy
+)
+
+// RedundancyMethod aggregates the redundancy of a feature against the selected features.
+type RedundancyMethod string
+
+const (
+	RedundancyAvg RedundancyMethod = "avg"
+	RedundancyMax RedundancyMethod = "max"
+)
+
+// Option sets a parameter of an MRMR run.
+type Option func(*ParasmRMR)
+
+// New returns the parameters for an MRMR run on data with opts applied, and the problems found by Validate.
+// Slices passed to options are kept, not copied, and are never modified by MRMR.
+func New(data DatamRMR, opts ...Option) (*ParasmRMR, error) {
+	paras := &ParasmRMR{Data: data}
+
+	for _, opt := range opts {
+		opt(paras)
+	}
+
+	return paras, paras.Validate()
+}
+
+func WithMethod(method Method) Option {
+	return func(paras *ParasmRMR) { paras.Method = method }
+}
+
+func WithCalculation(calculation Calculation) Option {
+	return func(paras *ParasmRMR) { paras.Calculation = calculation }
+}
+
+func WithRedundancyMethod(method RedundancyMethod) Option {
+	return func(paras *ParasmRMR) { paras.RedundancyMethod = method }
+}
+
+// WithMaxFeatures stops selection after n features.
+func WithMaxFeatures(n int) Option {
+	return func(paras *ParasmRMR) { paras.MaxFeatures = n }
+}
+
+// WithDiscretization discretizes the data into binSize bins, or sqrt(number of instances) bins if binSize is 0.
+func WithDiscretization(binSize int) Option {
+	return func(paras *ParasmRMR) {
+		paras.Discretization = true
+		paras.BinSize = binSize
+	}
+}
+
+// WithThreshold sets the quantization error threshold of "nmi-nmi". Unlike the Threshold field, 0 is kept.
+func WithThreshold(threshold float64) Option {
+	return func(paras *ParasmRMR) {
+		paras.Threshold = threshold
+		paras.thresholdSet = true
+	}
+}
+
+// WithRelevanceFunc replaces the relevance measure of the method.
+func WithRelevanceFunc(f func([]float64, []int) float64) Option {
+	return func(paras *ParasmRMR) { paras.RelevanceFunc = f }
+}
+
+// WithRedundancyFunc replaces the redundancy measure of the method.
+func WithRedundancyFunc(f func([]float64, []float64) float64) Option {
+	return func(paras *ParasmRMR) { paras.RedundancyFunc = f }
+}
+
+// WithCosts adjusts scores by the acquisition cost of each feature, in mode "penalty" or "ratio".
+func WithCosts(costs []float64, lambda float64, mode string) Option {
+	return func(paras *ParasmRMR) {
+		paras.Costs = costs
+		paras.CostLambda = lambda
+		paras.CostMode = mode
+	}
+}
+
+// WithBudget stops selection once no remaining feature fits in the budget. It requires WithCosts.
+func WithBudget(budget float64) Option {
+	return func(paras *ParasmRMR) { paras.Budget = budget }
+}
+
+// WithGroups sets the group label of each feature and how groups are selected, "joint" or "cap".
+func WithGroups(groups []int, mode string) Option {
+	return func(paras *ParasmRMR) {
+		paras.Groups = groups
+		paras.GroupMode = mode
+	}
+}
+
+// WithMaxPerGroup caps the number of selected features per group in GroupMode "cap".
+func WithMaxPerGroup(n int) Option {
+	return func(paras *ParasmRMR) { paras.MaxPerGroup = n }
+}
+
+// WithLabelAggregation sets how the relevance to multiple labels is combined: "mean", "max" or "powerset".
+func WithLabelAggregation(aggregation string) Option {
+	return func(paras *ParasmRMR) { paras.LabelAggregation = aggregation }
+}
+
+// WithRedundancyMemory caps the number of redundancy values kept for the returned map.
+func WithRedundancyMemory(entries int) Option {
+	return func(paras *ParasmRMR) { paras.RedundancyMemory = entries }
+}
+
+func WithTimeout(timeout time.Duration) Option {
+	return func(paras *ParasmRMR) { paras.Timeout = timeout }
+}
+
+func WithProgress(progress func(ProgressEvent)) Option {
+	return func(paras *ParasmRMR) { paras.Progress = progress }
+}
+
+func WithLogger(logger *slog.Logger) Option {
+	return func(paras *ParasmRMR) { paras.Logger = logger }
+}
+
+// WithCheckpoint writes the selection state to path every few steps, every 10 if every is 0.
+func WithCheckpoint(path string, every int) Option {
+	return func(paras *ParasmRMR) {
+		paras.CheckpointPath = path
+		paras.CheckpointEvery = every
+	}
+}
+
+// WithTieBreak sets how exact ties are broken: "lowest-index", "relevance" or "random" drawn from seed.
+func WithTieBreak(policy string, seed int64) Option {
+	return func(paras *ParasmRMR) {
+		paras.TieBreak = policy
+		paras.Seed = seed
+	}
+}
+
+// WithRelief replaces the relevance of the method by "relieff" with the given number of neighbors, 10 if 0,
+// or by "multisurf". samples instances are scored, all of them if 0.
+func WithRelief(relief string, neighbors, samples int) Option {
+	return func(paras *ParasmRMR) {
+		paras.Relief = relief
+		paras.ReliefNeighbors = neighbors
+		paras.ReliefSamples = samples
+	}
+}
+
+// WithMINormalization scales the mutual information of "mi-mi" and "nmi-nmi" into [0, 1]
+// with "su", "min", "sqrt" or "iqr", for relevance and redundancy alike.
+func WithMINormalization(normalization string) Option {
+	return func(paras *ParasmRMR) { paras.MINormalization = normalization }
+}
+
+// WithEstimator estimates the entropies of "mi-mi" and "nmi-nmi" by "plugin", "miller-madow", "chao-shen",
+// "james-stein" or "nsb", reducing the upward bias of mutual information on small samples.
+func WithEstimator(estimator string) Option {
+	return func(paras *ParasmRMR) { paras.Estimator = estimator }
+}
+
+// WithMIC sets the grid size exponent alpha and the clumps per column c searched by "mic-mic",
+// 0.6 and 15 if 0.
+func WithMIC(alpha, c float64) Option {
+	return func(paras *ParasmRMR) {
+		paras.MICAlpha = alpha
+		paras.MICClumps = c
+	}
+}
+
+// WithHSIC approximates "hsic-hsic" by "rff" (random Fourier features drawn from Seed) or "nystrom"
+// with the given number of components, 100 if 0.
+func WithHSIC(approximation string, components int) Option {
+	return func(paras *ParasmRMR) {
+		paras.HSICApproximation = approximation
+		paras.HSICComponents = components
+	}
+}
+
+// WithPruning limits the candidates considered at each step: "top" keeps the candidates most relevant features,
+// 10 per selected feature if 0, and "lazy" skips the redundancy of candidates that cannot win, with the same result.
+func WithPruning(mode string, candidates int) Option {
+	return func(paras *ParasmRMR) {
+		paras.Prune = mode
+		paras.PruneCandidates = candidates
+	}
+}
+
+// WithWorkers scores features on n goroutines.
+func WithWorkers(n int) Option {
+	return func(paras *ParasmRMR) { paras.Workers = n }
+}
+
+// Validate reports every problem with the parameters and data at once, joined into one error.
+// Zero values are valid wherever MRMR fills in a default.
+func (paras *ParasmRMR) Validate() error {
+	var errs []error
+	report := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	data := paras.Data
+	numInstances, numFeatures := data.dims()
+
+	sources := 0
+	for _, set := range []bool{len(data.X) > 0, data.Sparse != nil, data.Store != nil, data.Matrix != nil} {
+		if set {
+			sources++
+		}
+	}
+
+	switch {
+	case sources == 0:
+		report("no data: set one of X, Sparse, Store or Matrix")
+	case sources > 1:
+		report("X, Sparse, Store and Matrix are alternatives, set only one")
+	}
+
+	if data.Matrix != nil {
+		if err := data.Matrix.validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for i, row := range data.X {
+		if len(row) != numFeatures {
+			report("X row %d has %d features, expected %d", i, len(row), numFeatures)
+			break
+		}
+	}
+
+	if data.Labels != nil {
+		if err := validateLabels(data.Labels, numInstances); err != nil {
+			errs = append(errs, err)
+		}
+	} else if data.Class == nil && data.Store == nil {
+		report("no class: set Class or Labels")
+	} else if data.Class != nil && len(data.Class) != numInstances {
+		report("class has %d entries, expected one per instance (%d)", len(data.Class), numInstances)
+	}
+
+	method := paras.Method
+	if method == "" {
+		method = MethodNMINMI
+	}
+
+	spec, exists := LookupMethod(string(method))
+	if !exists {
+		report("unknown method %q, registered methods are %q", paras.Method, Methods())
+	}
+
+	switch paras.Calculation {
+	case "", CalculationDiff, CalculationQuo:
+	default:
+		report("unknown calculation %q, choose from %q or %q", paras.Calculation, CalculationDiff, CalculationQuo)
+	}
+
+	switch paras.RedundancyMethod {
+	case "", RedundancyAvg, RedundancyMax:
+	default:
+		report("unknown redundancy method %q, choose from %q or %q", paras.RedundancyMethod, RedundancyAvg, RedundancyMax)
+	}
+
+	if paras.BinSize < 0 {
+		report("BinSize must not be negative: %d", paras.BinSize)
+	}
+
+	if paras.MaxFeatures < 0 {
+		report("MaxFeatures must not be negative: %d", paras.MaxFeatures)
+	}
+
+	if paras.Threshold < 0 {
+		report("Threshold must not be negative: %v", paras.Threshold)
+	}
+
+	if paras.Costs != nil {
+		if err := validateCosts(paras.Costs, numFeatures); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	switch paras.CostMode {
+	case "", "penalty", "ratio":
+	default:
+		report("unknown cost mode %q, choose from 'penalty' or 'ratio'", paras.CostMode)
+	}
+
+	if paras.CostLambda < 0 {
+		report("CostLambda must not be negative: %v", paras.CostLambda)
+	}
+
+	if paras.Budget < 0 {
+		report("Budget must not be negative: %v", paras.Budget)
+	}
+
+	if paras.Budget > 0 && paras.Costs == nil {
+		report("Budget requires Costs for every feature")
+	}
+
+	switch paras.GroupMode {
+	case "":
+	case "cap", "joint":
+		if err := validateGroups(paras.Groups, numFeatures); err != nil {
+			errs = append(errs, err)
+		}
+	default:
+		report("unknown group mode %q, choose from 'joint' or 'cap'", paras.GroupMode)
+	}
+
+	if paras.GroupMode == "joint" {
+		if data.Sparse != nil || data.Store != nil || data.Matrix != nil {
+			report("GroupMode 'joint' requires dense data in X")
+		}
+		if exists && !spec.Information {
+			report("GroupMode 'joint' requires an information method such as %q or %q", MethodMIMI, MethodNMINMI)
+		}
+	}
+
+	if paras.MaxPerGroup < 0 {
+		report("MaxPerGroup must not be negative: %d", paras.MaxPerGroup)
+	}
+
+	switch paras.LabelAggregation {
+	case "", "mean", "max", "powerset":
+	default:
+		report("unknown label aggregation %q, choose from 'mean', 'max' or 'powerset'", paras.LabelAggregation)
+	}
+
+	switch paras.TieBreak {
+	case "", "lowest-index", "relevance", "random":
+	default:
+		report("unknown tie break %q, choose from 'lowest-index', 'relevance' or 'random'", paras.TieBreak)
+	}
+
+	switch paras.Relief {
+	case "":
+	case "relieff", "multisurf":
+		if data.Sparse != nil || data.Store != nil || data.Matrix != nil {
+			report("Relief requires dense data in X")
+		}
+		if paras.GroupMode == "joint" {
+			report("Relief cannot score groups in GroupMode 'joint'")
+		}
+	default:
+		report("unknown relief %q, choose from 'relieff' or 'multisurf'", paras.Relief)
+	}
+
+	switch paras.MINormalization {
+	case "":
+	case "su", "min", "sqrt", "iqr":
+		if !mutualInfoMethod(method) {
+			report("MINormalization requires method %q or %q, not %q", MethodMIMI, MethodNMINMI, method)
+		}
+	default:
+		report("unknown MI normalization %q, choose from 'su', 'min', 'sqrt' or 'iqr'", paras.MINormalization)
+	}
+
+	switch paras.Estimator {
+	case "", "plugin":
+	case "miller-madow", "chao-shen", "james-stein", "nsb":
+		if !mutualInfoMethod(method) {
+			report("Estimator requires method %q or %q, not %q", MethodMIMI, MethodNMINMI, method)
+		}
+	default:
+		report("unknown estimator %q, choose from 'plugin', 'miller-madow', 'chao-shen', 'james-stein' or 'nsb'", paras.Estimator)
+	}
+
+	if strings.EqualFold(string(method), string(MethodMICMIC)) {
+		if paras.MICAlpha < 0 || paras.MICAlpha > 1 || paras.MICClumps < 0 {
+			report("MIC needs 0 < MICAlpha <= 1 and MICClumps > 0, got %v and %v", paras.MICAlpha, paras.MICClumps)
+		}
+	} else if paras.MICAlpha != 0 || paras.MICClumps != 0 {
+		report("MICAlpha and MICClumps require method %q, not %q", MethodMICMIC, method)
+	}
+
+	switch paras.HSICApproximation {
+	case "":
+	case "rff", "nystrom":
+		if !strings.EqualFold(string(method), string(MethodHSICHSIC)) {
+			report("HSICApproximation requires method %q, not %q", MethodHSICHSIC, method)
+		}
+	default:
+		report("unknown HSIC approximation %q, choose from 'rff' or 'nystrom'", paras.HSICApproximation)
+	}
+
+	if paras.HSICComponents < 0 {
+		report("HSICComponents must not be negative: %d", paras.HSICComponents)
+	}
+
+	switch paras.Prune {
+	case "", "top":
+	case "lazy":
+		if !nonnegativeRedundancy(method, paras.RedundancyFunc != nil, paras.Estimator, paras.MINormalization) {
+			report("Prune 'lazy' requires the redundancy of a built-in method, with the plug-in estimator unless MINormalization is set")
+		}
+	default:
+		report("unknown pruning %q, choose from 'top' or 'lazy'", paras.Prune)
+	}
+
+	if paras.PruneCandidates < 0 {
+		report("PruneCandidates must not be negative: %d", paras.PruneCandidates)
+	}
+
+	if paras.ReliefNeighbors < 0 {
+		report("ReliefNeighbors must not be negative: %d", paras.ReliefNeighbors)
+	}
+
+	if paras.ReliefSamples < 0 {
+		report("ReliefSamples must not be negative: %d", paras.ReliefSamples)
+	}
+
+	if paras.RedundancyMemory < 0 {
+		report("RedundancyMemory must not be negative: %d", paras.RedundancyMemory)
+	}
+
+	if paras.Timeout < 0 {
+		report("Timeout must not be negative: %v", paras.Timeout)
+	}
+
+	if paras.CheckpointEvery < 0 {
+		report("CheckpointEvery must not be negative: %d", paras.CheckpointEvery)
+	}
+
+	if paras.Workers < 0 {
+		report("Workers must not be negative: %d", paras.Workers)
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,199 @@
+package mRMR
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+)
+
+// lazyBatch is the number of candidates brought up to date at once in lazy pruning,
+// so each selected feature is read once per batch rather than once per candidate.
+const lazyBatch = 64
+
+// mostRelevant returns the m most relevant of candidates, ties going to the smaller index, in ascending order.
+func mostRelevant(candidates []int, relevance []float64, m int) []int {
+	if len(candidates) <= m {
+		return candidates
+	}
+
+	top := append([]int(nil), candidates...)
+	sort.SliceStable(top, func(a, b int) bool {
+		return relevance[top[a]] > relevance[top[b]]
+	})
+	top = top[:m]
+	sort.Ints(top)
+
+	return top
+}
+
+// nonnegativeRedundancy reports whether redundancy is never negative, which the bounds of lazy pruning rely on.
+// The own measures of the built-in methods are, except mutual information from a bias-corrected estimator
+// that MINormalization does not clip. Registered and custom measures may be signed.
+func nonnegativeRedundancy(method Method, custom bool, estimator, normalization string) bool {
+	if custom {
+		return false
+	}
+
+	switch Method(strings.ToLower(string(method))) {
+	case MethodFSPearson, MethodFSSpearman, MethodFSKendall, MethodFSDcor, MethodMICMIC, MethodHSICHSIC:
+		return true
+	case MethodMIMI, MethodNMINMI:
+		return estimator == "" || estimator == "plugin" || normalization != ""
+	default:
+		return false
+	}
+}
+
+// lazyChoice is exactChoice without scoring candidates that cannot win. A candidate's redundancy is only
+// brought up to date with the selected features when needed; until then, the selected features it covers give
+// a lower bound on its redundancy, which nonnegativeRedundancy ensures is never negative, and so an upper bound on its score. Candidates are
+// brought up to date in order of that bound until no bound reaches the best score, so the choice, ties and
+// early stopping included, is that of exactChoice.
+func (paras *ParasmRMR) lazyChoice(ctx context.Context, data featureSet, state *selectionState, costs []float64) (int, float64, bool, error) {
+	// Open a row for the newest selected feature, filled as candidates are brought up to date
+	if state.store.Count() < len(state.selected) {
+		state.store.Add(state.selected[len(state.selected)-1], nil, nil)
+	}
+
+	calculation := string(paras.Calculation)
+	candidates := state.candidates
+	relevance := selectByIndex(state.relevance, candidates)
+
+	// bounds of the raw and the cost-adjusted score, exact once a candidate is up to date
+	bound := PairwiseOperation(relevance, paras.redundancies(state, candidates), calculation)
+	adjusted := bound
+	if costs != nil {
+		adjusted = CostAdjustment(bound, selectByIndex(costs, candidates), paras.CostLambda, paras.CostMode)
+	}
+
+	// update brings the candidates at positions up to date and makes their bounds exact
+	update := func(positions []int) error {
+		features := selectByIndex(candidates, positions)
+		if err := paras.cover(ctx, data, state, features); err != nil {
+			return err
+		}
+
+		exact := PairwiseOperation(selectByIndex(relevance, positions), paras.redundancies(state, features), calculation)
+		for i, pos := range positions {
+			bound[pos] = exact[i]
+			adjusted[pos] = exact[i]
+		}
+		if costs != nil {
+			exact = CostAdjustment(exact, selectByIndex(costs, features), paras.CostLambda, paras.CostMode)
+			for i, pos := range positions {
+				adjusted[pos] = exact[i]
+			}
+		}
+
+		return nil
+	}
+
+	order := make([]int, len(candidates))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return adjusted[order[a]] > adjusted[order[b]]
+	})
+
+	best := math.Inf(-1)
+	next := 0
+	for next < len(order) && adjusted[order[next]] >= best {
+		end := next
+		for end < len(order) && end-next < lazyBatch && adjusted[order[end]] >= best {
+			end++
+		}
+
+		if err := update(order[next:end]); err != nil {
+			return 0, 0, false, err
+		}
+
+		for _, pos := range order[next:end] {
+			best = math.Max(best, adjusted[pos])
+		}
+		next = end
+	}
+
+	// Early stopping: without costs the best score is the highest raw score, and with a penalty the best
+	// penalized score, on which exactChoice stops then. With a ratio to cost, candidates whose raw bound
+	// passes the limit are brought up to date until one of them does.
+	limit := 0.0
+	if paras.Calculation == "quo" {
+		limit = 1
+	}
+
+	improves := false
+	if paras.penalized(costs) {
+		improves = best > limit
+	} else {
+		for _, pos := range order[:next] {
+			improves = improves || bound[pos] > limit
+		}
+	}
+
+	if !improves && costs != nil && !paras.penalized(costs) {
+		var rest []int
+		for _, pos := range order[next:] {
+			if bound[pos] > limit {
+				rest = append(rest, pos)
+			}
+		}
+
+		for start := 0; start < len(rest) && !improves; start += lazyBatch {
+			batch := rest[start:min(start+lazyBatch, len(rest))]
+			if err := update(batch); err != nil {
+				return 0, 0, false, err
+			}
+
+			for _, pos := range batch {
+				improves = improves || bound[pos] > limit
+			}
+		}
+	}
+
+	paras.logger().Debug("lazy scores",
+		"step", len(state.selected)+1, "candidates", len(candidates), "evaluated", next, "best", best)
+
+	if !improves {
+		return 0, 0, false, nil
+	}
+
+	// Only evaluated candidates can tie with the best, and they are passed in candidate order
+	evaluated := append([]int(nil), order[:next]...)
+	sort.Ints(evaluated)
+
+	idx := chooseFeature(selectByIndex(adjusted, evaluated), selectByIndex(candidates, evaluated),
+		state.relevance, paras.TieBreak, paras.Seed, len(state.selected))
+	pos := evaluated[idx]
+
+	return pos, adjusted[pos], true, nil
+}
+
+// cover records the redundancy of features against every selected feature they do not cover yet,
+// in order of selection so that sums match those of exactChoice.
+func (paras *ParasmRMR) cover(ctx context.Context, data featureSet, state *selectionState, features []int) error {
+	for k, target := range state.selected {
+		var stale []int
+		for _, f := range features {
+			if state.covered[f] == k {
+				stale = append(stale, f)
+			}
+		}
+		if len(stale) == 0 {
+			continue
+		}
+
+		values, err := data.redundancy(ctx, stale, target)
+		if err != nil {
+			return err
+		}
+
+		for i, f := range stale {
+			state.store.Record(k, f, values[i])
+			state.covered[f] = k + 1
+		}
+	}
+
+	return nil
+}
@@ -7,8 +7,10 @@ func RedundancyUpdate(data [][]float64, featureToConsider []int, target int, red
 
 	data2 := getCol(data, target)
 
+	// one buffer serves every feature, as the redundancy function does not keep it
+	data1 := make([]float64, len(data))
 	for _, idx := range featureToConsider {
-		data1 := getCol(data, idx)
+		getColInto(data, idx, data1)
 		mi := redundancyFunc(data1, data2)
 		redundancyMap[[2]int{target, idx}] = mi
 	}
@@ -18,6 +20,11 @@ func RedundancyUpdate(data [][]float64, featureToConsider []int, target int, red
 
 // PearsonCorrelation returns the absolute value of pearson correlation coefficient
 func PearsonCorrelation(data1, data2 []float64) float64 {
+	return pearsonCorrelation(data1, data2)
+}
+
+// pearsonCorrelation is PearsonCorrelation of any Numeric type, summed in float64.
+func pearsonCorrelation[T Numeric](data1, data2 []T) float64 {
 	if len(data1) != len(data2) {
 		panic("feature slices must have the same length")
 	}
@@ -25,21 +32,19 @@ func PearsonCorrelation(data1, data2 []float64) float64 {
 	mean1 := mean(data1)
 	mean2 := mean(data2)
 
+	// deviations are taken on the fly so the inputs are left unchanged
+	sd1, sd2, cov := 0.0, 0.0, 0.0
 	for i := range data1 {
-		data1[i] -= mean1
-		data2[i] -= mean2
+		d1 := float64(data1[i]) - mean1
+		d2 := float64(data2[i]) - mean2
+
+		sd1 += d1 * d1
+		sd2 += d2 * d2
+		cov += d1 * d2
 	}
 
-	sd1 := sumOfSquares(data1)
 	sd1 = math.Sqrt(sd1)
-
-	sd2 := sumOfSquares(data2)
 	sd2 = math.Sqrt(sd2)
 
-	cov := 0.0
-	for i := range data1 {
-		cov += data1[i] * data2[i]
-	}
-
 	return math.Abs(cov / (sd1 * sd2))
 }
\ No newline at end of file
@@ -0,0 +1,132 @@
+package mRMR
+
+import "math"
+
+// RedundancyStore keeps pairwise redundancy values in one dense row per selected feature,
+// together with the running sum and maximum redundancy of every feature against all selected features.
+// The running values cover every selected feature even when rows are dropped to respect the memory cap.
+type RedundancyStore struct {
+	numFeatures int
+	maxRows     int // -1 keeps every row
+	selected    []int
+	rows        [][]float64
+	sum         []float64
+	max         []float64
+	count       int
+}
+
+// NewRedundancyStore returns a store for numFeatures features that keeps at most maxEntries redundancy values.
+// maxEntries = 0 keeps all of them. Whole rows of numFeatures values are kept, oldest dropped first,
+// and at least the latest row is kept even when maxEntries is below numFeatures.
+func NewRedundancyStore(numFeatures, maxEntries int) *RedundancyStore {
+	maxRows := -1
+	if maxEntries > 0 {
+		maxRows = max(maxEntries/numFeatures, 1)
+	}
+
+	// maxima start below any value, so negative redundancy is not hidden by a 0
+	maxima := make([]float64, numFeatures)
+	for f := range maxima {
+		maxima[f] = math.Inf(-1)
+	}
+
+	return &RedundancyStore{
+		numFeatures: numFeatures,
+		maxRows:     maxRows,
+		sum:         make([]float64, numFeatures),
+		max:         maxima,
+	}
+}
+
+// Add records the redundancy of each candidate against a newly selected feature.
+func (s *RedundancyStore) Add(selected int, candidates []int, values []float64) {
+	if len(candidates) != len(values) {
+		panic("Fail to store redundancy: Unequal length of data")
+	}
+
+	for i, f := range candidates {
+		val := values[i]
+
+		s.sum[f] += val
+		if val > s.max[f] {
+			s.max[f] = val
+		}
+	}
+	s.count++
+
+	var row []float64
+	if s.maxRows > 0 && len(s.rows) == s.maxRows {
+		// reuse the oldest row
+		row = s.rows[0]
+		s.rows = append(s.rows[:0], s.rows[1:]...)
+		s.selected = append(s.selected[:0], s.selected[1:]...)
+	} else {
+		row = make([]float64, s.numFeatures)
+	}
+
+	for f := range row {
+		row[f] = math.NaN()
+	}
+	for i, f := range candidates {
+		row[f] = values[i]
+	}
+
+	s.rows = append(s.rows, row)
+	s.selected = append(s.selected, selected)
+}
+
+// Record adds the redundancy of feature f against the k-th selected feature, for stores filled one
+// feature at a time after Add has opened the row of that selected feature with no candidates.
+func (s *RedundancyStore) Record(k, f int, val float64) {
+	s.sum[f] += val
+	if val > s.max[f] {
+		s.max[f] = val
+	}
+
+	if offset := s.count - len(s.rows); k >= offset {
+		s.rows[k-offset][f] = val
+	}
+}
+
+// Get returns the redundancy between a selected feature and feature f, if it is still stored.
+func (s *RedundancyStore) Get(selected, f int) (float64, bool) {
+	for k, sel := range s.selected {
+		if sel == selected {
+			val := s.rows[k][f]
+			return val, !math.IsNaN(val)
+		}
+	}
+
+	return 0, false
+}
+
+// Sum returns the sum of the redundancy of feature f against every selected feature.
+func (s *RedundancyStore) Sum(f int) float64 {
+	return s.sum[f]
+}
+
+// Max returns the maximum redundancy of feature f against every selected feature, or -Inf before any.
+func (s *RedundancyStore) Max(f int) float64 {
+	return s.max[f]
+}
+
+// Count returns the number of selected features added to the store.
+func (s *RedundancyStore) Count() int {
+	return s.count
+}
+
+// Map returns the stored redundancy values keyed by {selected feature, feature}.
+// With a memory cap it is partial: only the rows of the most recently selected features are left.
+func (s *RedundancyStore) Map() map[[2]int]float64 {
+	m := make(map[[2]int]float64)
+
+	for k, sel := range s.selected {
+		for f, val := range s.rows[k] {
+			if !math.IsNaN(val) {
+				m[[2]int{sel, f}] = val
+			}
+		}
+	}
+
+	return m
+}
@@ -0,0 +1,152 @@
+package mRMR
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// MethodSpec describes a relevance/redundancy method that can be selected by name through ParasmRMR.Method.
+type MethodSpec struct {
+	Relevance  func([]float64, []int) float64
+	Redundancy func([]float64, []float64) float64
+
+	// Normalization rescales the measures:
+	// "" leaves them unchanged, "minmax" min-max scales relevance,
+	// "nmi" divides relevance by log2(number of classes) and the summed redundancy by log2(QLevel).
+	Normalization string
+
+	// Preprocess prepares the data before scoring:
+	// "" discretizes into BinSize bins if Discretization is set,
+	// "quantize" always quantizes at the level reached by Threshold (QLevel).
+	Preprocess string
+
+	// Information marks measures of information on discrete values, such as mutual information.
+	// Only such methods can score the joint variable of a group in GroupMode "joint".
+	Information bool
+}
+
+var (
+	methodsMu sync.RWMutex
+	methods   = make(map[string]MethodSpec)
+)
+
+func init() {
+	RegisterMethod("mi-mi", MethodSpec{
+		Relevance:   MutualInfo[float64, int],
+		Redundancy:  MutualInfo[float64, float64],
+		Information: true,
+	})
+
+	RegisterMethod("fs-pearson", MethodSpec{
+		Relevance:     FStatistic,
+		Redundancy:    PearsonCorrelation,
+		Normalization: "minmax",
+	})
+
+	RegisterMethod("fs-spearman", MethodSpec{
+		Relevance:     FStatistic,
+		Redundancy:    SpearmanCorrelation,
+		Normalization: "minmax",
+	})
+
+	RegisterMethod("fs-kendall", MethodSpec{
+		Relevance:     FStatistic,
+		Redundancy:    KendallCorrelation,
+		Normalization: "minmax",
+	})
+
+	RegisterMethod("fs-dcor", MethodSpec{
+		Relevance:     FStatistic,
+		Redundancy:    DistanceCorrelation,
+		Normalization: "minmax",
+	})
+
+	RegisterMethod("mic-mic", MethodSpec{
+		Relevance:  MICRelevance(0.6, 15),
+		Redundancy: MICRedundancy(0.6, 15),
+	})
+
+	RegisterMethod("hsic-hsic", MethodSpec{
+		Relevance:  NormalizedClassHSIC,
+		Redundancy: NormalizedHSIC,
+	})
+
+	RegisterMethod("nmi-nmi", MethodSpec{
+		Relevance:     MutualInfo[float64, int],
+		Redundancy:    MutualInfo[float64, float64],
+		Normalization: "nmi",
+		Preprocess:    "quantize",
+		Information:   true,
+	})
+}
+
+// RegisterMethod makes a method available as ParasmRMR.Method under name, which is case-insensitive.
+// It panics if the name is taken or the spec is incomplete.
+func RegisterMethod(name string, spec MethodSpec) {
+	name = strings.ToLower(name)
+
+	if name == "" {
+		panic("method name must not be empty")
+	}
+
+	if spec.Relevance == nil || spec.Redundancy == nil {
+		panic(fmt.Sprintf("method %q needs both a relevance and a redundancy function", name))
+	}
+
+	switch spec.Normalization {
+	case "", "minmax", "nmi":
+	default:
+		panic("Invalid normalization. Choose from '', 'minmax' or 'nmi'")
+	}
+
+	switch spec.Preprocess {
+	case "", "quantize":
+	default:
+		panic("Invalid preprocessing. Choose from '' or 'quantize'")
+	}
+
+	methodsMu.Lock()
+	defer methodsMu.Unlock()
+
+	if _, exists := methods[name]; exists {
+		panic(fmt.Sprintf("method %q is already registered", name))
+	}
+
+	methods[name] = spec
+}
+
+// LookupMethod returns the method registered under name.
+func LookupMethod(name string) (MethodSpec, bool) {
+	methodsMu.RLock()
+	defer methodsMu.RUnlock()
+
+	spec, exists := methods[strings.ToLower(name)]
+
+	return spec, exists
+}
+
+// Methods returns the names of all registered methods in alphabetical order.
+func Methods() []string {
+	methodsMu.RLock()
+	defer methodsMu.RUnlock()
+
+	names := make([]string, 0, len(methods))
+	for name := range methods {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// spec returns the registered method selected by Method.
+func (paras *ParasmRMR) spec() MethodSpec {
+	spec, exists := LookupMethod(string(paras.Method))
+	if !exists {
+		panic(fmt.Sprintf("Invalid method %q. Choose from '%s'", paras.Method, strings.Join(Methods(), "', '")))
+	}
+
+	return spec
+}
@@ -1,16 +1,14 @@
 package mRMR
 
-import (
-	"math"
-)
-
 // Relevance computes the relevance of each feature with respect to the class and returns the scores as a slice.
 func Relevance(data [][]float64, class []int, relevanceFunc func([]float64, []int) float64) []float64 {
 	n := len(data[0])
 	relevance := make([]float64, n)
 
+	// one buffer serves every feature, as the relevance function does not keep it
+	feature := make([]float64, len(data))
 	for i := 0; i < n; i++ {
-		feature := getCol(data, i)
+		getColInto(data, i, feature)
 		mi := relevanceFunc(feature, class)
 		relevance[i] = mi 
 	}
@@ -34,19 +32,12 @@ func MutualInfo[T1, T2 Numeric](data1 []T1, data2 []T2) float64 {
 func shannonEntropy[T Numeric](sample []T) float64 {
 	n := float64(len(sample))
 	count := make(map[float64]int)
-	sum := 0.0
 
 	for _, val := range sample {
 		count[float64(val)] ++
 	}
 
-	for _, val := range count {
-		prob := float64(val) / n
-		temp := prob * math.Log2(prob)
-		sum += temp
-	}
-
-	return -sum 
+	return estimateCounts(count, n, len(count), "")
 }
 
 func shannonJointEntropy[T1, T2 Numeric](data1 []T1, data2 []T2) float64 {
@@ -56,7 +47,6 @@ func shannonJointEntropy[T1, T2 Numeric](data1 []T1, data2 []T2) float64 {
 
 	n := float64(len(data1))
 	count := make(map[[2]float64]int)
-	sum := 0.0
 
 	for i, val1 := range data1 {
 		val2 := data2[i]
@@ -65,17 +55,16 @@ func shannonJointEntropy[T1, T2 Numeric](data1 []T1, data2 []T2) float64 {
 		count[data]++
 	}
 
-	for _, val := range count {
-		prob := float64(val) / n
-		temp := prob * math.Log2(prob)
-		sum += temp
-	}
-
-	return -sum
+	return estimateCounts(count, n, len(count), "")
 }
 
 // FStatistic returns the f-statistic of feature and class. 
 func FStatistic(feature []float64, class []int) float64 {
+	return fStatistic(feature, class)
+}
+
+// fStatistic is FStatistic of any Numeric type, summed in float64.
+func fStatistic[T Numeric](feature []T, class []int) float64 {
 	bigN := float64(len(feature))
 
 	normalized_ss := squaresOfSum(feature) / bigN
@@ -88,12 +77,11 @@ func FStatistic(feature []float64, class []int) float64 {
 	ssbn -= normalized_ss
 
 	mean := mean(feature)
-	for i := range feature {
-		feature[i] -= mean
+	sstotal := 0.0
+	for _, val := range feature {
+		sstotal += (float64(val) - mean) * (float64(val) - mean)
 	}
 
-	sstotal := sumOfSquares(feature)
-
 	sswn := sstotal - ssbn
 	dfbn := float64(len(groups)) - 1  
 	dfwn := bigN - float64(len(groups))
@@ -104,43 +92,33 @@ func FStatistic(feature []float64, class []int) float64 {
 	return msb / msw
 }
 
-func groupByClass(data []float64, class []int) [][]float64 {
+func groupByClass[T Numeric](data []T, class []int) [][]T {
 	
 	if len(data) != len(class) {
 		panic("data and class slices must have the same length")
 	}
 	
-	gmap := make(map[int][]float64)
+	gmap := make(map[int][]T)
 	for i, cls := range class {
 		gmap[cls] = append(gmap[cls], data[i])
 	}
 
-	grouped := make([][]float64, 0, len(gmap))
-	for _, values := range gmap {
-		grouped = append(grouped, values)
+	// ordered by class so sums over groups do not depend on map order
+	grouped := make([][]T, 0, len(gmap))
+	for _, cls := range sortedKeys(gmap) {
+		grouped = append(grouped, gmap[cls])
 	}
 
 	return grouped
 }
 
 // return (a + b + ...)^2
-func squaresOfSum(data []float64) float64 {
+func squaresOfSum[T Numeric](data []T) float64 {
 	sum := 0.0
 
 	for _, val := range data {
-		sum += val 
+		sum += float64(val)
 	}
 
 	return sum * sum
 }
-
-// return a^2 + b^2 + ...
-func sumOfSquares(data []float64) float64 {
-	sum := 0.0
-
-	for _, val := range data {
-		sum += val * val 
-	} 
-
-	return sum 
-}
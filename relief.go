@@ -0,0 +1,259 @@
+package mRMR
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// reliefDiscreteLimit is the largest number of distinct values for which a feature is treated as discrete by Relief.
+const reliefDiscreteLimit = 10
+
+// ReliefF scores each feature by how much better it separates instances from their nearest misses
+// than from their nearest hits, which also credits features that only matter in interactions.
+// neighbors nearest hits and misses of every class are used for each of samples instances drawn with seed,
+// or for every instance if samples is 0. Features with at most 10 distinct values are compared as discrete,
+// others by their difference scaled to the feature's range.
+func ReliefF(data [][]float64, class []int, neighbors, samples int, seed int64) []float64 {
+	r, _ := reliefF(context.Background(), data, class, neighbors, samples, seed)
+	return r
+}
+
+// MultiSURF is ReliefF without a neighbor count: the neighbors of an instance are all instances closer than
+// the mean distance from it minus half the standard deviation of those distances.
+func MultiSURF(data [][]float64, class []int, samples int, seed int64) []float64 {
+	r, _ := multiSURF(context.Background(), data, class, samples, seed)
+	return r
+}
+
+// reliefRelevance scores the features of X with the Relief variant set by Relief.
+func (paras *ParasmRMR) reliefRelevance(ctx context.Context, class []int) ([]float64, error) {
+	switch paras.Relief {
+	case "relieff":
+		return reliefF(ctx, paras.Data.X, class, paras.ReliefNeighbors, paras.ReliefSamples, paras.Seed)
+	case "multisurf":
+		return multiSURF(ctx, paras.Data.X, class, paras.ReliefSamples, paras.Seed)
+	default:
+		panic("Invalid relief. Choose from 'relieff' or 'multisurf'")
+	}
+}
+
+func reliefF(ctx context.Context, data [][]float64, class []int, neighbors, samples int, seed int64) ([]float64, error) {
+	if len(data) != len(class) {
+		panic("data and class slices must have the same length")
+	}
+
+	if neighbors <= 0 {
+		panic("ReliefF needs a positive number of neighbors")
+	}
+
+	rd := newReliefData(data, class)
+	sampled := reliefSample(len(data), samples, seed)
+	weights := make([]float64, rd.numFeatures)
+
+	for _, i := range sampled {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		dist := rd.distances(i)
+
+		// nearest neighbors of each class, excluding the instance itself
+		byClass := make(map[int][]int)
+		for j := range data {
+			if j != i {
+				byClass[class[j]] = append(byClass[class[j]], j)
+			}
+		}
+
+		for _, c := range sortedKeys(byClass) {
+			members := byClass[c]
+			sort.SliceStable(members, func(a, b int) bool { return dist[members[a]] < dist[members[b]] })
+			if len(members) > neighbors {
+				members = members[:neighbors]
+			}
+
+			weight := -1.0
+			if c != class[i] {
+				weight = rd.missWeight(class[i], c)
+			}
+
+			rd.accumulate(weights, i, members, weight)
+		}
+	}
+
+	for f := range weights {
+		weights[f] /= float64(len(sampled))
+	}
+
+	return weights, nil
+}
+
+func multiSURF(ctx context.Context, data [][]float64, class []int, samples int, seed int64) ([]float64, error) {
+	if len(data) != len(class) {
+		panic("data and class slices must have the same length")
+	}
+
+	rd := newReliefData(data, class)
+	sampled := reliefSample(len(data), samples, seed)
+	weights := make([]float64, rd.numFeatures)
+
+	for _, i := range sampled {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		dist := rd.distances(i)
+
+		sum, sq := 0.0, 0.0
+		for j, d := range dist {
+			if j != i {
+				sum += d
+				sq += d * d
+			}
+		}
+		n := float64(len(dist) - 1)
+		avg := sum / n
+		threshold := avg - math.Sqrt(math.Max(sq/n-avg*avg, 0))/2
+
+		byClass := make(map[int][]int)
+		for j, d := range dist {
+			if j != i && d < threshold {
+				byClass[class[j]] = append(byClass[class[j]], j)
+			}
+		}
+
+		// miss weights are renormalized over the classes that have near misses
+		missTotal := 0.0
+		for _, c := range sortedKeys(byClass) {
+			if c != class[i] {
+				missTotal += rd.missWeight(class[i], c)
+			}
+		}
+
+		for _, c := range sortedKeys(byClass) {
+			members := byClass[c]
+			weight := -1.0
+			if c != class[i] {
+				weight = rd.missWeight(class[i], c) / missTotal
+			}
+
+			rd.accumulate(weights, i, members, weight)
+		}
+	}
+
+	for f := range weights {
+		weights[f] /= float64(len(sampled))
+	}
+
+	return weights, nil
+}
+
+// reliefData holds what Relief needs to compare instances feature by feature.
+type reliefData struct {
+	data        [][]float64
+	numFeatures int
+	span        []float64 // range of each numeric feature
+	discrete    []bool
+	prior       map[int]float64
+}
+
+func newReliefData(data [][]float64, class []int) *reliefData {
+	numFeatures := len(data[0])
+	rd := &reliefData{
+		data:        data,
+		numFeatures: numFeatures,
+		span:        make([]float64, numFeatures),
+		discrete:    make([]bool, numFeatures),
+		prior:       make(map[int]float64),
+	}
+
+	for f := 0; f < numFeatures; f++ {
+		col := getCol(data, f)
+		distinct := make(map[float64]bool)
+
+		min, max := col[0], col[0]
+		for _, val := range col {
+			min = math.Min(min, val)
+			max = math.Max(max, val)
+			if len(distinct) <= reliefDiscreteLimit {
+				distinct[val] = true
+			}
+		}
+
+		rd.span[f] = max - min
+		rd.discrete[f] = len(distinct) <= reliefDiscreteLimit
+	}
+
+	for c, count := range classCounts(class) {
+		rd.prior[c] = float64(count) / float64(len(class))
+	}
+
+	return rd
+}
+
+// diff is the difference of two instances in feature f, in [0, 1].
+func (rd *reliefData) diff(f, a, b int) float64 {
+	x, y := rd.data[a][f], rd.data[b][f]
+
+	if rd.discrete[f] {
+		if x == y {
+			return 0
+		}
+		return 1
+	}
+
+	if rd.span[f] == 0 {
+		return 0
+	}
+
+	return math.Abs(x-y) / rd.span[f]
+}
+
+// distances returns the distance from instance i to every instance, summed over features.
+func (rd *reliefData) distances(i int) []float64 {
+	dist := make([]float64, len(rd.data))
+
+	for j := range rd.data {
+		for f := 0; f < rd.numFeatures; f++ {
+			dist[j] += rd.diff(f, i, j)
+		}
+	}
+
+	return dist
+}
+
+// missWeight is the share of class c among the classes other than own.
+func (rd *reliefData) missWeight(own, c int) float64 {
+	return rd.prior[c] / (1 - rd.prior[own])
+}
+
+// accumulate adds weight times the mean difference between instance i and the neighbors to every feature.
+func (rd *reliefData) accumulate(weights []float64, i int, neighbors []int, weight float64) {
+	if len(neighbors) == 0 {
+		return
+	}
+
+	for f := range weights {
+		sum := 0.0
+		for _, j := range neighbors {
+			sum += rd.diff(f, i, j)
+		}
+
+		weights[f] += weight * sum / float64(len(neighbors))
+	}
+}
+
+// reliefSample returns the instances to score: all of them, or samples drawn without replacement.
+func reliefSample(n, samples int, seed int64) []int {
+	if samples <= 0 || samples >= n {
+		all := make([]int, n)
+		for i := range all {
+			all[i] = i
+		}
+		return all
+	}
+
+	return rand.New(rand.NewSource(seed)).Perm(n)[:samples]
+}
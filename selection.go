@@ -0,0 +1,272 @@
+package mRMR
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+)
+
+// selectionState is the progress of a greedy mRMR search. Redundancy is accumulated per
+// candidate as features are selected, so each step only scores candidates against the newest one.
+type selectionState struct {
+	relevance  []float64 // relevance of every feature
+	candidates []int     // features still to consider, ascending
+	selected   []int
+	store      *RedundancyStore
+	spent      float64
+	groupCount map[int]int
+	lastScore  float64 // score of the newest selected feature
+	covered    []int   // per feature, the number of selected features its redundancy covers in lazy pruning
+}
+
+// ProgressEvent describes a finished stage of an MRMR run, passed to ParasmRMR.Progress.
+type ProgressEvent struct {
+	Stage      string        // "relevance" once relevance is computed, then "selection" after each step
+	Step       int           // number of features selected so far
+	Feature    int           // feature selected at this step (a group position in GroupMode "joint"), -1 for "relevance"
+	Score      float64       // score of that feature
+	Candidates int           // features still to consider
+	StepTime   time.Duration // time spent on this stage
+	Elapsed    time.Duration // time since the run started
+}
+
+// selection runs the greedy mRMR search over the features of data, starting from ck.resume if set.
+// If ctx is done, the features selected so far are returned with the context's error.
+func (paras *ParasmRMR) selection(ctx context.Context, data featureSet, costs []float64, ck *checkpointer) ([]int, []float64, map[[2]int]float64, error) {
+	start := time.Now()
+	logger := paras.logger()
+
+	var state *selectionState
+	if ck.resume != nil {
+		state = ck.resume.state()
+		logger.Info("resumed from checkpoint", "step", len(state.selected), "candidates", len(state.candidates))
+	} else {
+		var err error
+		state, err = paras.newSelection(ctx, data)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		logger.Info("relevance computed",
+			"features", data.numFeatures(), "candidates", len(state.candidates), "elapsed", time.Since(start))
+
+		paras.report(ProgressEvent{
+			Stage:      "relevance",
+			Feature:    -1,
+			Candidates: len(state.candidates),
+			StepTime:   time.Since(start),
+			Elapsed:    time.Since(start),
+		})
+	}
+
+	for len(state.selected) < paras.MaxFeatures {
+		stepStart := time.Now()
+
+		ok, err := paras.step(ctx, data, state, costs)
+		if err != nil {
+			logger.Info("selection cancelled", "step", len(state.selected), "error", err)
+			return state.selected, state.relevance, state.store.Map(), errors.Join(err, ck.save(paras, state))
+		}
+		if !ok {
+			logger.Info("selection stopped early", "step", len(state.selected), "candidates", len(state.candidates))
+			break
+		}
+
+		logger.Info("feature selected",
+			"step", len(state.selected),
+			"feature", state.selected[len(state.selected)-1],
+			"score", state.lastScore,
+			"candidates", len(state.candidates),
+			"step_time", time.Since(stepStart),
+			"elapsed", time.Since(start))
+
+		paras.report(ProgressEvent{
+			Stage:      "selection",
+			Step:       len(state.selected),
+			Feature:    state.selected[len(state.selected)-1],
+			Score:      state.lastScore,
+			Candidates: len(state.candidates),
+			StepTime:   time.Since(stepStart),
+			Elapsed:    time.Since(start),
+		})
+
+		if ck.due(len(state.selected)) {
+			if err := ck.save(paras, state); err != nil {
+				return state.selected, state.relevance, state.store.Map(), err
+			}
+		}
+	}
+
+	return state.selected, state.relevance, state.store.Map(), nil
+}
+
+// report passes an event to the progress callback, if any.
+func (paras *ParasmRMR) report(event ProgressEvent) {
+	if paras.Progress != nil {
+		paras.Progress(event)
+	}
+}
+
+// newSelection computes the relevance of every feature and the initial candidates.
+func (paras *ParasmRMR) newSelection(ctx context.Context, data featureSet) (*selectionState, error) {
+	relevanceAll, err := paras.relevance(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Filter out features with zero relevance
+	featuresToConsider := make([]int, 0, data.numFeatures())
+	for i, val := range relevanceAll {
+		if val > 0 {
+			featuresToConsider = append(featuresToConsider, i)
+		}
+	}
+
+	if paras.Prune == "top" {
+		featuresToConsider = mostRelevant(featuresToConsider, relevanceAll, paras.PruneCandidates)
+	}
+
+	var covered []int
+	if paras.Prune == "lazy" {
+		covered = make([]int, data.numFeatures())
+	}
+
+	if paras.normalization() == "minmax" {
+		relevanceAll = MinMaxNormalization(relevanceAll)
+	}
+
+	return &selectionState{
+		relevance:  relevanceAll,
+		candidates: featuresToConsider,
+		selected:   make([]int, 0, paras.MaxFeatures),
+		store:      NewRedundancyStore(data.numFeatures(), paras.RedundancyMemory),
+		groupCount: make(map[int]int),
+		covered:    covered,
+	}, nil
+}
+
+// step selects one more feature. It returns false if selection should stop.
+func (paras *ParasmRMR) step(ctx context.Context, data featureSet, state *selectionState, costs []float64) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	// Drop features that no longer fit in the budget
+	if paras.Budget > 0 {
+		state.candidates = affordable(state.candidates, costs, paras.Budget-state.spent)
+		if len(state.candidates) == 0 {
+			return false, nil
+		}
+	}
+
+	choose := paras.exactChoice
+	if paras.Prune == "lazy" {
+		choose = paras.lazyChoice
+	}
+
+	idx, score, ok, err := choose(ctx, data, state, costs)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	feature := state.candidates[idx]
+	state.selected = append(state.selected, feature)
+	state.lastScore = score
+	state.candidates = Delete(state.candidates, idx)
+
+	if costs != nil {
+		state.spent += costs[feature]
+	}
+
+	// Drop the rest of a group once it has MaxPerGroup members selected
+	if paras.GroupMode == "cap" {
+		g := paras.Groups[feature]
+		state.groupCount[g]++
+		if state.groupCount[g] >= paras.MaxPerGroup {
+			state.candidates = dropGroup(state.candidates, paras.Groups, g)
+		}
+	}
+
+	return true, nil
+}
+
+// exactChoice scores every candidate against all selected features and returns the position of the best one
+// and its score. It returns false if no candidate improves the selection.
+func (paras *ParasmRMR) exactChoice(ctx context.Context, data featureSet, state *selectionState, costs []float64) (int, float64, bool, error) {
+	// Accumulate redundancy against the newest selected feature only
+	if state.store.Count() < len(state.selected) {
+		lastSelectedF := state.selected[len(state.selected)-1]
+		values, err := data.redundancy(ctx, state.candidates, lastSelectedF)
+		if err != nil {
+			return 0, 0, false, err
+		}
+		state.store.Add(lastSelectedF, state.candidates, values)
+	}
+
+	relevance := selectByIndex(state.relevance, state.candidates)
+	redundancy := paras.redundancies(state, state.candidates)
+	score := PairwiseOperation(relevance, redundancy, string(paras.Calculation))
+
+	paras.logger().Debug("scores",
+		"step", len(state.selected)+1, "relevance", relevance, "redundancy", redundancy, "score", score)
+
+	adjusted := score
+	if costs != nil {
+		adjusted = CostAdjustment(score, selectByIndex(costs, state.candidates), paras.CostLambda, paras.CostMode)
+	}
+
+	// Early stopping, on the penalized score in "penalty" mode since the penalty is part of the criterion.
+	// A ratio to cost has no threshold of its own, so "ratio" mode stops on the score.
+	criterion := score
+	if paras.penalized(costs) {
+		criterion = adjusted
+	}
+
+	if (paras.Calculation == "diff" && CheckIfAllNegative(criterion)) ||
+		(paras.Calculation == "quo" && CheckIfAllSmallerOne(criterion)) {
+		return 0, 0, false, nil
+	}
+
+	score = adjusted
+
+	idx := chooseFeature(score, state.candidates, state.relevance, paras.TieBreak, paras.Seed, len(state.selected))
+
+	return idx, score[idx], true, nil
+}
+
+// penalized reports whether costs are subtracted from the score, which early stopping then uses.
+func (paras *ParasmRMR) penalized(costs []float64) bool {
+	return costs != nil && paras.CostMode == "penalty"
+}
+
+// redundancies returns the redundancy of each feature against the selected features, from the sums and
+// maxima in the store. In lazy pruning it covers only the selected features the store has values for.
+func (paras *ParasmRMR) redundancies(state *selectionState, features []int) []float64 {
+	redundancy := make([]float64, len(features))
+
+	if len(state.selected) == 0 {
+		return redundancy
+	}
+
+	divisor := float64(state.store.Count())
+	if paras.normalization() == "nmi" {
+		divisor = math.Log2(float64(paras.QLevel))
+	}
+
+	for i, f := range features {
+		switch paras.RedundancyMethod {
+		case "avg":
+			redundancy[i] = state.store.Sum(f) / divisor
+		case "max":
+			redundancy[i] = state.store.Max(f)
+
+			// a feature not yet covered in lazy pruning has no maximum, and its redundancy is never negative
+			if math.IsInf(redundancy[i], -1) {
+				redundancy[i] = 0
+			}
+		}
+	}
+
+	return redundancy
+}
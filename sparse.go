@@ -0,0 +1,548 @@
+package mRMR
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// SparseMatrix is an instance-by-feature matrix in compressed sparse column (CSC) form.
+// The nonzeros of column j are RowIdx[ColPtr[j]:ColPtr[j+1]] with values Values[ColPtr[j]:ColPtr[j+1]],
+// rows in ascending order. Every other entry is zero.
+type SparseMatrix struct {
+	Rows   int
+	Cols   int
+	ColPtr []int
+	RowIdx []int
+	Values []float64
+}
+
+// SparseVector is one column of a SparseMatrix: the nonzeros of a vector of length N.
+type SparseVector struct {
+	N   int
+	Idx []int
+	Val []float64
+}
+
+// NewSparseCSC checks a CSC matrix and returns it.
+func NewSparseCSC(rows, cols int, colPtr, rowIdx []int, values []float64) *SparseMatrix {
+	if len(colPtr) != cols+1 {
+		panic(fmt.Sprintf("colPtr has %d entries, expected %d", len(colPtr), cols+1))
+	}
+
+	if len(rowIdx) != len(values) || colPtr[cols] != len(values) {
+		panic("rowIdx, values and colPtr disagree on the number of nonzeros")
+	}
+
+	for j := 0; j < cols; j++ {
+		for k := colPtr[j]; k < colPtr[j+1]; k++ {
+			if rowIdx[k] < 0 || rowIdx[k] >= rows {
+				panic(fmt.Sprintf("row index %d out of range in column %d", rowIdx[k], j))
+			}
+			if k > colPtr[j] && rowIdx[k] <= rowIdx[k-1] {
+				panic(fmt.Sprintf("row indices of column %d are not strictly ascending", j))
+			}
+		}
+	}
+
+	return &SparseMatrix{Rows: rows, Cols: cols, ColPtr: colPtr, RowIdx: rowIdx, Values: values}
+}
+
+// NewSparseCSR converts a matrix in compressed sparse row (CSR) form to a SparseMatrix.
+func NewSparseCSR(rows, cols int, rowPtr, colIdx []int, values []float64) *SparseMatrix {
+	if len(rowPtr) != rows+1 {
+		panic(fmt.Sprintf("rowPtr has %d entries, expected %d", len(rowPtr), rows+1))
+	}
+
+	if len(colIdx) != len(values) || rowPtr[rows] != len(values) {
+		panic("colIdx, values and rowPtr disagree on the number of nonzeros")
+	}
+
+	colPtr := make([]int, cols+1)
+	for _, j := range colIdx {
+		if j < 0 || j >= cols {
+			panic(fmt.Sprintf("column index %d out of range", j))
+		}
+		colPtr[j+1]++
+	}
+
+	for j := 0; j < cols; j++ {
+		colPtr[j+1] += colPtr[j]
+	}
+
+	rowIdx := make([]int, len(values))
+	vals := make([]float64, len(values))
+	next := append([]int(nil), colPtr[:cols]...)
+
+	// walking rows in order keeps the row indices of each column ascending
+	for i := 0; i < rows; i++ {
+		for k := rowPtr[i]; k < rowPtr[i+1]; k++ {
+			j := colIdx[k]
+			rowIdx[next[j]] = i
+			vals[next[j]] = values[k]
+			next[j]++
+		}
+	}
+
+	return NewSparseCSC(rows, cols, colPtr, rowIdx, vals)
+}
+
+// SparseFromDense keeps the nonzero entries of a dense instance-by-feature matrix.
+func SparseFromDense(data [][]float64) *SparseMatrix {
+	rows := len(data)
+	cols := len(data[0])
+
+	colPtr := make([]int, cols+1)
+	var rowIdx []int
+	var values []float64
+
+	for j := 0; j < cols; j++ {
+		for i := 0; i < rows; i++ {
+			if data[i][j] != 0 {
+				rowIdx = append(rowIdx, i)
+				values = append(values, data[i][j])
+			}
+		}
+		colPtr[j+1] = len(values)
+	}
+
+	return &SparseMatrix{Rows: rows, Cols: cols, ColPtr: colPtr, RowIdx: rowIdx, Values: values}
+}
+
+// Col returns column j without copying.
+func (m *SparseMatrix) Col(j int) SparseVector {
+	start, end := m.ColPtr[j], m.ColPtr[j+1]
+
+	return SparseVector{N: m.Rows, Idx: m.RowIdx[start:end], Val: m.Values[start:end]}
+}
+
+// dense writes the vector with its zeros filled in to buf, allocated if it is too short, and returns it.
+func (a SparseVector) dense(buf []float64) []float64 {
+	if cap(buf) < a.N {
+		buf = make([]float64, a.N)
+	}
+	buf = buf[:a.N]
+
+	for i := range buf {
+		buf[i] = 0
+	}
+
+	for k, row := range a.Idx {
+		buf[row] = a.Val[k]
+	}
+
+	return buf
+}
+
+// DiscretizationSparse bins the nonzero values of each column into bins 1..binSize and keeps zeros as 0,
+// so the result has the same sparsity pattern.
+func DiscretizationSparse(m *SparseMatrix, binSize int) *SparseMatrix {
+	values := make([]float64, len(m.Values))
+
+	for j := 0; j < m.Cols; j++ {
+		col := m.Col(j)
+		if len(col.Val) == 0 {
+			continue
+		}
+
+		min, max := col.Val[0], col.Val[0]
+		for _, val := range col.Val {
+			if val > max {
+				max = val
+			}
+			if val < min {
+				min = val
+			}
+		}
+
+		binWidth := (max - min) / float64(binSize)
+
+		for k, val := range col.Val {
+			binIdx := 0
+			if binWidth > 0 {
+				binIdx = int(math.Floor((val - min) / binWidth))
+			}
+
+			if binIdx == binSize {
+				binIdx--
+			}
+
+			values[m.ColPtr[j]+k] = float64(binIdx + 1)
+		}
+	}
+
+	return &SparseMatrix{Rows: m.Rows, Cols: m.Cols, ColPtr: m.ColPtr, RowIdx: m.RowIdx, Values: values}
+}
+
+// quantizeSparse bins the columns of m as quantizing their dense columns would, visiting only nonzeros.
+// Each value is replaced by its bin less the bin of zero, so zeros stay implicit and the counts,
+// and hence the information measures, are those of the quantized columns.
+func quantizeSparse(m *SparseMatrix, level int) *SparseMatrix {
+	values := make([]float64, len(m.Values))
+
+	for j := 0; j < m.Cols; j++ {
+		col := m.Col(j)
+		if len(col.Val) == 0 {
+			continue
+		}
+
+		min, max := col.Val[0], col.Val[0]
+		if len(col.Idx) < col.N {
+			min, max = 0, 0
+		}
+
+		for _, val := range col.Val {
+			if val > max {
+				max = val
+			}
+			if val < min {
+				min = val
+			}
+		}
+
+		binWidth := (max - min) / float64(level)
+		zero := binIndex(0, min, binWidth, level)
+
+		for k, val := range col.Val {
+			values[m.ColPtr[j]+k] = float64(binIndex(val, min, binWidth, level) - zero)
+		}
+	}
+
+	return &SparseMatrix{Rows: m.Rows, Cols: m.Cols, ColPtr: m.ColPtr, RowIdx: m.RowIdx, Values: values}
+}
+
+// SparseMutualInfo calculates the mutual information between two sparse vectors.
+// Only nonzeros are visited; the count of shared zeros is inferred from the length.
+func SparseMutualInfo(a, b SparseVector) float64 {
+	ha, hb, hab := sparseEntropies(a, b, "")
+
+	return ha + hb - hab
+}
+
+// sparseEntropies returns the entropies of two sparse vectors and their joint entropy, by an Entropy estimator.
+func sparseEntropies(a, b SparseVector, estimator string) (float64, float64, float64) {
+	if a.N != b.N {
+		panic("Fail to calculate mutual information: Unequal length of data")
+	}
+
+	n := float64(a.N)
+	joint := make(map[[2]float64]int)
+	union := 0
+
+	i, j := 0, 0
+	for i < len(a.Idx) || j < len(b.Idx) {
+		var key [2]float64
+
+		switch {
+		case j == len(b.Idx) || (i < len(a.Idx) && a.Idx[i] < b.Idx[j]):
+			key = [2]float64{a.Val[i], 0}
+			i++
+		case i == len(a.Idx) || b.Idx[j] < a.Idx[i]:
+			key = [2]float64{0, b.Val[j]}
+			j++
+		default:
+			key = [2]float64{a.Val[i], b.Val[j]}
+			i++
+			j++
+		}
+
+		joint[key]++
+		union++
+	}
+
+	if zeros := a.N - union; zeros > 0 {
+		joint[[2]float64{0, 0}] += zeros
+	}
+
+	count1, count2 := sparseHistogram(a), sparseHistogram(b)
+
+	return estimateCounts(count1, n, len(count1), estimator),
+		estimateCounts(count2, n, len(count2), estimator),
+		estimateCounts(joint, n, len(count1)*len(count2), estimator)
+}
+
+// SparseClassMutualInfo calculates the mutual information between a sparse vector and the class.
+func SparseClassMutualInfo(a SparseVector, class []int) float64 {
+	return sparseClassMutualInfo(a, class, classCounts(class))
+}
+
+func sparseClassMutualInfo(a SparseVector, class []int, counts map[int]int) float64 {
+	ha, hc, hac := sparseClassEntropies(a, class, counts, "")
+
+	return ha + hc - hac
+}
+
+// sparseClassEntropies returns the entropies of a sparse vector and the class, and their joint entropy,
+// by an Entropy estimator.
+func sparseClassEntropies(a SparseVector, class []int, counts map[int]int, estimator string) (float64, float64, float64) {
+	if a.N != len(class) {
+		panic("Fail to calculate mutual information: Unequal length of data")
+	}
+
+	n := float64(a.N)
+	joint := make(map[[2]float64]int)
+	zeros := make(map[int]int, len(counts))
+
+	for c, val := range counts {
+		zeros[c] = val
+	}
+
+	for k, row := range a.Idx {
+		joint[[2]float64{a.Val[k], float64(class[row])}]++
+		zeros[class[row]]--
+	}
+
+	for c, val := range zeros {
+		if val > 0 {
+			joint[[2]float64{0, float64(c)}] += val
+		}
+	}
+
+	count := sparseHistogram(a)
+
+	return estimateCounts(count, n, len(count), estimator),
+		estimateCounts(counts, n, len(counts), estimator),
+		estimateCounts(joint, n, len(count)*len(counts), estimator)
+}
+
+// SparsePearsonCorrelation returns the absolute value of pearson correlation coefficient of two sparse vectors.
+func SparsePearsonCorrelation(a, b SparseVector) float64 {
+	if a.N != b.N {
+		panic("feature slices must have the same length")
+	}
+
+	n := float64(a.N)
+	mean1, mean2 := sparseSum(a)/n, sparseSum(b)/n
+
+	// deviations are summed over the union of nonzeros, and the rows zero in both are added at once,
+	// which avoids the cancellation of subtracting squared sums
+	sd1, sd2, cov := 0.0, 0.0, 0.0
+	union := 0
+
+	i, j := 0, 0
+	for i < len(a.Idx) || j < len(b.Idx) {
+		val1, val2 := 0.0, 0.0
+
+		switch {
+		case j == len(b.Idx) || (i < len(a.Idx) && a.Idx[i] < b.Idx[j]):
+			val1 = a.Val[i]
+			i++
+		case i == len(a.Idx) || b.Idx[j] < a.Idx[i]:
+			val2 = b.Val[j]
+			j++
+		default:
+			val1, val2 = a.Val[i], b.Val[j]
+			i++
+			j++
+		}
+
+		d1, d2 := val1-mean1, val2-mean2
+		sd1 += d1 * d1
+		sd2 += d2 * d2
+		cov += d1 * d2
+		union++
+	}
+
+	zeros := float64(a.N - union)
+	sd1 += zeros * mean1 * mean1
+	sd2 += zeros * mean2 * mean2
+	cov += zeros * mean1 * mean2
+
+	return math.Abs(cov / (math.Sqrt(sd1) * math.Sqrt(sd2)))
+}
+
+// SparseFStatistic returns the f-statistic of a sparse feature and class.
+func SparseFStatistic(a SparseVector, class []int) float64 {
+	return sparseFStatistic(a, class, classCounts(class))
+}
+
+func sparseFStatistic(a SparseVector, class []int, counts map[int]int) float64 {
+	if a.N != len(class) {
+		panic("data and class slices must have the same length")
+	}
+
+	bigN := float64(a.N)
+	sum := sparseSum(a)
+
+	groupSum := make(map[int]float64, len(counts))
+	for k, row := range a.Idx {
+		groupSum[class[row]] += a.Val[k]
+	}
+
+	ssbn := 0.0
+	for _, c := range sortedKeys(counts) {
+		ssbn += groupSum[c] * groupSum[c] / float64(counts[c])
+	}
+	ssbn -= sum * sum / bigN
+
+	// summed as deviations from the mean, as FStatistic does
+	mean := sum / bigN
+	sstotal := float64(a.N-len(a.Val)) * mean * mean
+	for _, val := range a.Val {
+		sstotal += (val - mean) * (val - mean)
+	}
+
+	sswn := sstotal - ssbn
+	dfbn := float64(len(counts)) - 1
+	dfwn := bigN - float64(len(counts))
+
+	msb := ssbn / dfbn
+	msw := sswn / dfwn
+
+	return msb / msw
+}
+
+// sparseSet serves features from a sparse matrix. The built-in measures visit only the nonzeros of m; the others,
+// left nil, are replaced by dense measures that see each column of raw densified into a buffer as it is read.
+type sparseSet struct {
+	m               *SparseMatrix
+	raw             *SparseMatrix
+	prepare         func([]float64) []float64
+	relevanceFunc   func(SparseVector, []int, map[int]int) float64
+	redundancyFunc  func(SparseVector, SparseVector) float64
+	relevanceDense  func([]float64, []int) float64
+	redundancyDense func([]float64, []float64) float64
+	workers         int
+}
+
+// newSparseSet returns the feature set of paras.Data.Sparse. The own measures of methods 'mi-mi', 'nmi-nmi' and
+// 'fs-pearson', with mutual information from an Entropy estimator and normalized by a MINormalization if set,
+// visit only nonzeros, those of 'nmi-nmi' on columns quantized once up front. Other registered methods and custom
+// measures see each column densified, and quantized if the method asks, as it is read.
+func newSparseSet(paras *ParasmRMR) sparseSet {
+	m := paras.Data.Sparse
+	s := sparseSet{m: m, raw: m, workers: paras.Workers}
+
+	relevance, redundancy, builtin := sparseMeasures(string(paras.Method), paras.MINormalization, paras.Estimator)
+
+	// Discretization has already binned the nonzeros, so only quantization is left
+	if paras.spec().Preprocess == "quantize" {
+		s.prepare = paras.prepare()
+		if builtin && (paras.methodRelevance || paras.methodRedundancy) {
+			s.m = quantizeSparse(m, paras.QLevel)
+		}
+	}
+
+	if builtin && paras.methodRelevance {
+		s.relevanceFunc = relevance
+	} else {
+		s.relevanceDense = paras.RelevanceFunc
+	}
+
+	if builtin && paras.methodRedundancy {
+		s.redundancyFunc = redundancy
+	} else {
+		s.redundancyDense = paras.RedundancyFunc
+	}
+
+	return s
+}
+
+// sparseMeasures returns the nonzero-only measures of a built-in method.
+func sparseMeasures(method, normalization, estimator string) (func(SparseVector, []int, map[int]int) float64, func(SparseVector, SparseVector) float64, bool) {
+	switch strings.ToLower(method) {
+	case "mi-mi", "nmi-nmi":
+		if normalization != "" || (estimator != "" && estimator != "plugin") {
+			relevance := func(a SparseVector, class []int, counts map[int]int) float64 {
+				ha, hc, hac := sparseClassEntropies(a, class, counts, estimator)
+				return combineMI(ha, hc, hac, normalization)
+			}
+			redundancy := func(a, b SparseVector) float64 {
+				ha, hb, hab := sparseEntropies(a, b, estimator)
+				return combineMI(ha, hb, hab, normalization)
+			}
+			return relevance, redundancy, true
+		}
+		return sparseClassMutualInfo, SparseMutualInfo, true
+	case "fs-pearson":
+		return sparseFStatistic, SparsePearsonCorrelation, true
+	default:
+		return nil, nil, false
+	}
+}
+
+// column densifies feature j into buf and prepares it.
+func (s sparseSet) column(j int, buf []float64) []float64 {
+	col := s.raw.Col(j).dense(buf)
+
+	if s.prepare != nil {
+		col = s.prepare(col)
+	}
+
+	return col
+}
+
+func (s sparseSet) numFeatures() int {
+	return s.m.Cols
+}
+
+func (s sparseSet) relevance(ctx context.Context, class []int) ([]float64, error) {
+	if s.relevanceFunc == nil {
+		bufs := buffers[float64](s.workers, s.raw.Rows)
+
+		return scoreEach(ctx, s.workers, s.raw.Cols, func(w, j int) float64 {
+			return s.relevanceDense(s.column(j, bufs[w]), class)
+		})
+	}
+
+	counts := classCounts(class)
+
+	return scoreEach(ctx, s.workers, s.m.Cols, func(_, j int) float64 {
+		return s.relevanceFunc(s.m.Col(j), class, counts)
+	})
+}
+
+func (s sparseSet) redundancy(ctx context.Context, candidates []int, target int) ([]float64, error) {
+	if s.redundancyFunc == nil {
+		// the target is densified once per step, and each candidate into the buffer of its worker
+		data2 := s.column(target, nil)
+		bufs := buffers[float64](s.workers, s.raw.Rows)
+
+		return scoreEach(ctx, s.workers, len(candidates), func(w, i int) float64 {
+			return s.redundancyDense(s.column(candidates[i], bufs[w]), data2)
+		})
+	}
+
+	data2 := s.m.Col(target)
+
+	return scoreEach(ctx, s.workers, len(candidates), func(_, i int) float64 {
+		return s.redundancyFunc(s.m.Col(candidates[i]), data2)
+	})
+}
+
+// sparseHistogram counts the values of a sparse vector, with the zero count inferred.
+func sparseHistogram(a SparseVector) map[float64]int {
+	count := make(map[float64]int)
+
+	for _, val := range a.Val {
+		count[val]++
+	}
+
+	if zeros := a.N - len(a.Idx); zeros > 0 {
+		count[0] += zeros
+	}
+
+	return count
+}
+
+// sparseSum returns the sum of a sparse vector.
+func sparseSum(a SparseVector) float64 {
+	sum := 0.0
+
+	for _, val := range a.Val {
+		sum += val
+	}
+
+	return sum
+}
+
+func classCounts(class []int) map[int]int {
+	counts := make(map[int]int)
+
+	for _, c := range class {
+		counts[c]++
+	}
+
+	return counts
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/PQMark/mRMR"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResume(t *testing.T) {
+	data := generateDiscrete(200, 40)
+	path := filepath.Join(t.TempDir(), "run.ckpt")
+
+	full := mRMR.ParasmRMR{
+		Data:             data,
+		Method:           "mi-mi",
+		MaxFeatures:      12,
+		RedundancyMethod: "max",
+	}
+	expected, expectedRelevance, _ := full.MRMR()
+
+	// interrupt a checkpointed run after 5 steps
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	interrupted := mRMR.ParasmRMR{
+		Data:             data,
+		Method:           "mi-mi",
+		MaxFeatures:      12,
+		RedundancyMethod: "max",
+		CheckpointPath:   path,
+		CheckpointEvery:  2,
+		Progress: func(event mRMR.ProgressEvent) {
+			if event.Step == 5 {
+				cancel()
+			}
+		},
+	}
+
+	partial, _, _, err := interrupted.MRMRContext(ctx)
+	if !errors.Is(err, context.Canceled) || len(partial) != 5 {
+		t.Fatalf("Expected 5 features and context.Canceled, got %v and %v", partial, err)
+	}
+
+	// parameters come from the checkpoint
+	resumed := mRMR.ParasmRMR{Data: data}
+	result, relevance, _, err := resumed.Resume(context.Background(), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if fmt.Sprint(result) != fmt.Sprint(expected) || fmt.Sprint(relevance) != fmt.Sprint(expectedRelevance) {
+		t.Errorf("Resumed run differs: expected %v, got %v", expected, result)
+	}
+}
+
+func TestCheckpointDefaultEvery(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run.ckpt")
+
+	// CheckpointEvery left at 0 saves every 10 steps
+	paras := mRMR.ParasmRMR{
+		Data:           generateDiscrete(200, 40),
+		Method:         "mi-mi",
+		MaxFeatures:    12,
+		CheckpointPath: path,
+	}
+	paras.MRMR()
+
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("Expected a checkpoint with the default CheckpointEvery, got %v", err)
+	}
+}
+
+func TestResumeChangedData(t *testing.T) {
+	data := generateDiscrete(200, 40)
+	path := filepath.Join(t.TempDir(), "run.ckpt")
+
+	paras := mRMR.ParasmRMR{
+		Data:            data,
+		Method:          "mi-mi",
+		MaxFeatures:     4,
+		CheckpointPath:  path,
+		CheckpointEvery: 2,
+	}
+	paras.MRMR()
+
+	changed := generateDiscrete(200, 40)
+	changed.X[10][3]++
+
+	resumed := mRMR.ParasmRMR{Data: changed}
+	if _, _, _, err := resumed.Resume(context.Background(), path); err == nil {
+		t.Errorf("Expected an error when resuming on changed data")
+	}
+}
+
+func TestResumeRestoresCostsAndGroups(t *testing.T) {
+	data := generateDiscrete(200, 40)
+	path := filepath.Join(t.TempDir(), "run.ckpt")
+
+	costs := make([]float64, 40)
+	groups := make([]int, 40)
+	for j := range costs {
+		costs[j] = 1 + float64(j%5)
+		groups[j] = j / 4
+	}
+
+	full := mRMR.ParasmRMR{
+		Data:        data,
+		Method:      "mi-mi",
+		MaxFeatures: 10,
+		Costs:       costs,
+		CostLambda:  0.01,
+		Groups:      groups,
+		GroupMode:   "cap",
+		MaxPerGroup: 2,
+	}
+	expected, _, _ := full.MRMR()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	interrupted := full
+	interrupted.CheckpointPath = path
+	interrupted.CheckpointEvery = 2
+	interrupted.Progress = func(event mRMR.ProgressEvent) {
+		if event.Step == 4 {
+			cancel()
+		}
+	}
+
+	if _, _, _, err := interrupted.MRMRContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+
+	// costs and groups come from the checkpoint
+	resumed := mRMR.ParasmRMR{Data: data}
+	result, _, _, err := resumed.Resume(context.Background(), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if fmt.Sprint(result) != fmt.Sprint(expected) {
+		t.Errorf("Resumed run differs: expected %v, got %v", expected, result)
+	}
+}
+
+func TestResumeCustomMeasures(t *testing.T) {
+	data := generateDiscrete(200, 40)
+	path := filepath.Join(t.TempDir(), "run.ckpt")
+	redundancy := func(data1, data2 []float64) float64 { return mRMR.MutualInfo(data1, data2) / 2 }
+
+	full := mRMR.ParasmRMR{Data: data, Method: "mi-mi", MaxFeatures: 8, RedundancyFunc: redundancy}
+	expected, _, _ := full.MRMR()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	interrupted := full
+	interrupted.CheckpointPath = path
+	interrupted.CheckpointEvery = 2
+	interrupted.Progress = func(event mRMR.ProgressEvent) {
+		if event.Step == 4 {
+			cancel()
+		}
+	}
+
+	if _, _, _, err := interrupted.MRMRContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+
+	missing := mRMR.ParasmRMR{Data: data}
+	if _, _, _, err := missing.Resume(context.Background(), path); err == nil {
+		t.Errorf("Expected an error when resuming without the custom RedundancyFunc")
+	}
+
+	resumed := mRMR.ParasmRMR{Data: data, RedundancyFunc: redundancy}
+	result, _, _, err := resumed.Resume(context.Background(), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if fmt.Sprint(result) != fmt.Sprint(expected) {
+		t.Errorf("Resumed run differs: expected %v, got %v", expected, result)
+	}
+}
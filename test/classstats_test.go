@@ -0,0 +1,139 @@
+package main
+
+import (
+	"github.com/PQMark/mRMR"
+	"math"
+	"testing"
+)
+
+var (
+	statFeature = []float64{1.2, 3.4, 2.2, 5.1, 4.4, 2.2, 6.0, 3.3, 7.1, 5.5, 4.0, 6.6}
+	statClass   = []int{0, 0, 0, 1, 1, 0, 1, 0, 1, 1, 1, 0}
+	statClass3  = []int{0, 0, 0, 1, 1, 0, 2, 1, 2, 2, 1, 2}
+)
+
+func TestClassStatistics(t *testing.T) {
+	discrete := []float64{0, 1, 0, 2, 1, 0, 2, 1, 2, 2, 1, 2}
+
+	// the chi-square p-value is erfc(sqrt(x/2)) with 1 degree of freedom and exp(-x/2) with 2
+	tests := []struct {
+		name         string
+		test         func([]float64, []int) (float64, float64)
+		feature      []float64
+		class        []int
+		stat, pvalue float64
+	}{
+		{"ChiSquareTest", mRMR.ChiSquareTest, discrete, statClass, 4.8, 0.09071795328941251},
+		{"ChiSquareTest 2x2", mRMR.ChiSquareTest, []float64{0, 0, 0, 1, 1, 0, 1, 1, 0, 1}, []int{0, 0, 1, 1, 0, 1, 1, 0, 0, 1}, 0.4, math.Erfc(math.Sqrt(0.2))},
+		{"KruskalWallisTest", mRMR.KruskalWallisTest, statFeature, statClass3, 9.301754385964918, 0.009553218238024675},
+	}
+
+	for _, tt := range tests {
+		stat, p := tt.test(tt.feature, tt.class)
+		if math.Abs(stat-tt.stat) > 1e-9 || math.Abs(p-tt.pvalue) > 1e-9 {
+			t.Errorf("%s: expected (%v, %v), got (%v, %v)", tt.name, tt.stat, tt.pvalue, stat, p)
+		}
+	}
+}
+
+func TestFTest(t *testing.T) {
+	f, p := mRMR.FTest(statFeature, statClass3)
+
+	if f != mRMR.FStatistic(statFeature, statClass3) {
+		t.Errorf("Expected the f-statistic %v, got %v", mRMR.FStatistic(statFeature, statClass3), f)
+	}
+
+	// F(2, d) survival is (1 + 2f/d)^(-d/2)
+	if expected := math.Pow(1+2*f/9, -4.5); math.Abs(p-expected) > 1e-12 {
+		t.Errorf("Expected p-value %v, got %v", expected, p)
+	}
+}
+
+func TestWelchTTest(t *testing.T) {
+	stat, p := mRMR.WelchTTest(statFeature, statClass)
+
+	if math.Abs(stat-2.465330980592207) > 1e-12 {
+		t.Errorf("Expected t = 2.465330980592207, got %v", stat)
+	}
+
+	// two-sided tail of the t density with the Welch-Satterthwaite degrees of freedom
+	expected := 2 * integrate(tDensity(8.171735974563234), stat, stat+1000, 200000)
+	if math.Abs(p-expected) > 1e-6 {
+		t.Errorf("Expected p-value %v, got %v", expected, p)
+	}
+
+	// a class with a single member has no variance
+	stat, p = mRMR.WelchTTest([]float64{1, 2, 3, 10}, []int{0, 0, 0, 1})
+	if stat != 0 || p != 1 {
+		t.Errorf("Expected t = 0 and p = 1 with a single-member class, got %v and %v", stat, p)
+	}
+}
+
+func TestAUC(t *testing.T) {
+	auc, p := mRMR.AUCTest(statFeature, statClass)
+	if math.Abs(auc-0.8611111111111112) > 1e-12 {
+		t.Errorf("Expected AUC 0.8611111111111112, got %v", auc)
+	}
+	if p <= 0 || p >= 0.1 {
+		t.Errorf("Expected a small p-value, got %v", p)
+	}
+
+	// flipping the classes flips the AUC, but not the relevance
+	flipped := make([]int, len(statClass))
+	for i, c := range statClass {
+		flipped[i] = 1 - c
+	}
+
+	if reverse, _ := mRMR.AUCTest(statFeature, flipped); math.Abs(reverse-(1-auc)) > 1e-12 {
+		t.Errorf("Expected AUC %v for flipped classes, got %v", 1-auc, reverse)
+	}
+	if mRMR.AUC(statFeature, flipped) != mRMR.AUC(statFeature, statClass) {
+		t.Errorf("Expected AUC relevance to ignore the direction")
+	}
+}
+
+func TestPValueRelevance(t *testing.T) {
+	data := GenerateData(500)
+	relevance := mRMR.Relevance(data.X, data.Class, mRMR.PValueRelevance(mRMR.WelchTTest))
+
+	// features 0 and 1 determine the class, 4 and 5 are noise
+	if relevance[0] <= relevance[4] || relevance[1] <= relevance[5] {
+		t.Errorf("Expected informative features to score higher, got %v", relevance)
+	}
+
+	paras := mRMR.ParasmRMR{
+		Data:          data,
+		Method:        mRMR.MethodFSPearson,
+		RelevanceFunc: mRMR.PValueRelevance(mRMR.KruskalWallisTest),
+		MaxFeatures:   2,
+	}
+	if selectedFeatures, _, _ := paras.MRMR(); len(selectedFeatures) != 2 {
+		t.Errorf("Expected 2 features, got %v", selectedFeatures)
+	}
+}
+
+func tDensity(df float64) func(float64) float64 {
+	lg1, _ := math.Lgamma((df + 1) / 2)
+	lg2, _ := math.Lgamma(df / 2)
+	norm := math.Exp(lg1-lg2) / math.Sqrt(df*math.Pi)
+
+	return func(x float64) float64 {
+		return norm * math.Pow(1+x*x/df, -(df+1)/2)
+	}
+}
+
+// integrate applies Simpson's rule with steps intervals.
+func integrate(f func(float64) float64, a, b float64, steps int) float64 {
+	h := (b - a) / float64(steps)
+	sum := f(a) + f(b)
+
+	for i := 1; i < steps; i++ {
+		weight := 2.0
+		if i%2 == 1 {
+			weight = 4
+		}
+		sum += weight * f(a+float64(i)*h)
+	}
+
+	return sum * h / 3
+}
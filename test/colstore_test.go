@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"github.com/PQMark/mRMR"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func TestColumnStore(t *testing.T) {
+	data := GenerateData(500)
+	dir := t.TempDir()
+	storePath := filepath.Join(dir, "data.col")
+
+	features := mRMR.ConvertCSV(writeCSV(t, dir, data), storePath, 1, true)
+	store := mRMR.OpenColumnStore(storePath)
+	defer store.Close()
+
+	if len(features) != 6 || features[0] != "f0" || features[5] != "f5" {
+		t.Errorf("Expected feature names f0..f5, got %v", features)
+	}
+
+	if store.Rows() != 500 || store.Cols() != 6 {
+		t.Fatalf("Expected 500 x 6 store, got %d x %d", store.Rows(), store.Cols())
+	}
+
+	// labels are numbered in order of appearance
+	labelOf := make(map[int]int)
+	for i, c := range store.Class() {
+		if _, exists := labelOf[data.Class[i]]; !exists {
+			labelOf[data.Class[i]] = c
+		}
+		if c != labelOf[data.Class[i]] {
+			t.Fatalf("Class mismatch at row %d. Got %d, expected %d", i, c, labelOf[data.Class[i]])
+		}
+	}
+
+	for j := 0; j < store.Cols(); j++ {
+		for i, val := range store.Column(j, nil) {
+			if val != data.X[i][j] {
+				t.Fatalf("Value mismatch at row %d, column %d. Got %v, expected %v", i, j, val, data.X[i][j])
+			}
+		}
+	}
+}
+
+func TestMRMRColumnStore(t *testing.T) {
+	data := GenerateData(500)
+	dir := t.TempDir()
+	storePath := filepath.Join(dir, "data.col")
+
+	mRMR.ConvertCSV(writeCSV(t, dir, data), storePath, 1, true)
+	store := mRMR.OpenColumnStore(storePath)
+	defer store.Close()
+
+	for _, method := range []mRMR.Method{"mi-mi", "fs-pearson", "nmi-nmi"} {
+		dense := mRMR.ParasmRMR{
+			Data:           mRMR.DatamRMR{X: data.X, Class: data.Class},
+			Method:         method,
+			Discretization: method == "mi-mi",
+		}
+		disk := mRMR.ParasmRMR{
+			Data:           mRMR.DatamRMR{Store: store},
+			Method:         method,
+			Discretization: method == "mi-mi",
+		}
+
+		expected, _, _ := dense.MRMR()
+		result, _, _ := disk.MRMR()
+
+		if fmt.Sprint(result) != fmt.Sprint(expected) {
+			t.Errorf("%s: expected %v, got %v", method, expected, result)
+		}
+
+		// lazy pruning scores against older selected features too
+		disk.Prune = "lazy"
+		if result, _, _ := disk.MRMR(); fmt.Sprint(result) != fmt.Sprint(expected) {
+			t.Errorf("%s lazy: expected %v, got %v", method, expected, result)
+		}
+	}
+}
+
+// writeCSV writes data with the class in the first column and a header row.
+func writeCSV(t *testing.T, dir string, data mRMR.DatamRMR) string {
+	path := filepath.Join(dir, "data.csv")
+
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	fmt.Fprint(file, "class")
+	for j := range data.X[0] {
+		fmt.Fprintf(file, ",f%d", j)
+	}
+	fmt.Fprintln(file)
+
+	for i, row := range data.X {
+		fmt.Fprintf(file, "c%d", data.Class[i])
+		for _, val := range row {
+			fmt.Fprint(file, ",", strconv.FormatFloat(val, 'g', -1, 64))
+		}
+		fmt.Fprintln(file)
+	}
+
+	return path
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"github.com/PQMark/mRMR"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestRankCorrelations(t *testing.T) {
+	x := []float64{1, 2, 3, 4, 5, 6, 7, 8}
+	y := []float64{2, 1, 4, 3, 3, 8, 6, 6}
+
+	tests := []struct {
+		name     string
+		f        func([]float64, []float64) float64
+		expected float64
+	}{
+		{"SpearmanCorrelation", mRMR.SpearmanCorrelation, 0.8193365776101958},
+		{"KendallCorrelation", mRMR.KendallCorrelation, 0.5929994533288809},
+		{"DistanceCorrelation", mRMR.DistanceCorrelation, 0.8752734219954269},
+	}
+
+	for _, tt := range tests {
+		if result := tt.f(x, y); math.Abs(result-tt.expected) > 1e-12 {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, result)
+		}
+	}
+}
+
+func TestKendallMatchesPairCount(t *testing.T) {
+	r := rand.New(rand.NewSource(5))
+	x := make([]float64, 300)
+	y := make([]float64, 300)
+	for i := range x {
+		// few distinct values, so both slices have many ties
+		x[i] = float64(r.Intn(8))
+		y[i] = x[i] + float64(r.Intn(5))
+	}
+
+	concordant, discordant, tiedX, tiedY := 0, 0, 0, 0
+	for i := range x {
+		for j := i + 1; j < len(x); j++ {
+			dx, dy := x[i]-x[j], y[i]-y[j]
+			switch {
+			case dx == 0 && dy == 0:
+			case dx == 0:
+				tiedX++
+			case dy == 0:
+				tiedY++
+			case dx*dy > 0:
+				concordant++
+			default:
+				discordant++
+			}
+		}
+	}
+	expected := math.Abs(float64(concordant-discordant) /
+		math.Sqrt(float64(concordant+discordant+tiedX)*float64(concordant+discordant+tiedY)))
+
+	if result := mRMR.KendallCorrelation(x, y); math.Abs(result-expected) > 1e-12 {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestDistanceCorrelationNonMonotone(t *testing.T) {
+	x := []float64{-3, -2, -1, 0, 1, 2, 3}
+	y := make([]float64, len(x))
+	for i, val := range x {
+		y[i] = val * val
+	}
+
+	if pearson := mRMR.PearsonCorrelation(x, y); pearson > 1e-12 {
+		t.Fatalf("Expected no linear correlation, got %v", pearson)
+	}
+
+	if result := mRMR.DistanceCorrelation(x, y); math.Abs(result-0.5053197494397452) > 1e-12 {
+		t.Errorf("Expected 0.5053197494397452, got %v", result)
+	}
+
+	if result := mRMR.DistanceCorrelation(x, x); math.Abs(result-1) > 1e-12 {
+		t.Errorf("Expected 1 for identical slices, got %v", result)
+	}
+}
+
+func TestCorrelationMethods(t *testing.T) {
+	// the redundant copies of features 0 and 1 should not both be selected
+	for _, method := range []mRMR.Method{mRMR.MethodFSSpearman, mRMR.MethodFSKendall, mRMR.MethodFSDcor} {
+		paras := mRMR.ParasmRMR{
+			Data:        GenerateData(300),
+			Method:      method,
+			MaxFeatures: 2,
+		}
+
+		selectedFeatures, _, _ := paras.MRMR()
+		if fmt.Sprint(selectedFeatures) == "[0 2]" || fmt.Sprint(selectedFeatures) == "[2 0]" {
+			t.Errorf("%s: selected a redundant pair %v", method, selectedFeatures)
+		}
+	}
+}
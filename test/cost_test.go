@@ -0,0 +1,111 @@
+package main
+
+import (
+	"github.com/PQMark/mRMR"
+	"math"
+	"testing"
+)
+
+func TestCostAdjustment(t *testing.T) {
+	const epsilon = 1e-6
+
+	testCases := []struct {
+		score    []float64
+		costs    []float64
+		lambda   float64
+		mode     string
+		expected []float64
+	}{
+		{
+			score:    []float64{0.5, 0.4, 0.1},
+			costs:    []float64{1, 0.5, 0},
+			lambda:   0.2,
+			mode:     "penalty",
+			expected: []float64{0.3, 0.3, 0.1},
+		},
+		{
+			score:    []float64{0.5, 0.4, -0.2},
+			costs:    []float64{2, 0.5, 4},
+			mode:     "ratio",
+			expected: []float64{0.25, 0.8, -0.05},
+		},
+	}
+
+	for _, tt := range testCases {
+		result := mRMR.CostAdjustment(tt.score, tt.costs, tt.lambda, tt.mode)
+
+		for i := range result {
+			if math.Abs(result[i]-tt.expected[i]) > epsilon {
+				t.Errorf("Expected %v, got %v", tt.expected, result)
+				break
+			}
+		}
+	}
+}
+
+func TestMRMRBudget(t *testing.T) {
+	data := GenerateData(2000)
+	costs := []float64{5, 1, 1, 1, 1, 1}
+
+	paras := mRMR.ParasmRMR{
+		Data:           data,
+		Method:         "mi-mi",
+		Discretization: true,
+		BinSize:        10,
+		Costs:          costs,
+		Budget:         2.5,
+	}
+
+	selectedFeatures, _, _ := paras.MRMR()
+
+	spent := 0.0
+	for _, f := range selectedFeatures {
+		if f == 0 {
+			t.Errorf("Feature 0 costs more than the budget but was selected: %v", selectedFeatures)
+		}
+		spent += costs[f]
+	}
+
+	if spent > 2.5 {
+		t.Errorf("Selected features %v cost %v, exceeding budget 2.5", selectedFeatures, spent)
+	}
+}
+
+func TestMRMRPenaltyEarlyStopping(t *testing.T) {
+	data := generateDiscrete(300, 30)
+
+	costs := make([]float64, 30)
+	for j := range costs {
+		costs[j] = 1 + float64(j%4)
+	}
+
+	for _, prune := range []string{"", "lazy"} {
+		for _, lambda := range []float64{0.02, 10} {
+			var scores []float64
+			paras := mRMR.ParasmRMR{
+				Data:       data,
+				Method:     "mi-mi",
+				Costs:      costs,
+				CostLambda: lambda,
+				Prune:      prune,
+				Progress: func(event mRMR.ProgressEvent) {
+					if event.Stage == "selection" {
+						scores = append(scores, event.Score)
+					}
+				},
+			}
+
+			selected, _, _ := paras.MRMR()
+
+			// selection stops once no feature is worth its cost, though raw scores are still positive
+			if len(selected) == 30 {
+				t.Errorf("%q, lambda %v: expected selection to stop early, got %v", prune, lambda, selected)
+			}
+			for _, score := range scores {
+				if score <= 0 {
+					t.Errorf("%q, lambda %v: selected a feature with penalized score %v", prune, lambda, score)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"github.com/PQMark/mRMR"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestEntropyEstimators(t *testing.T) {
+	x := []float64{0, 0, 0, 1, 1, 2}
+	plugin := 1.4591479170272448
+
+	tests := []struct {
+		name      string
+		data      []float64
+		estimator string
+		expected  float64
+	}{
+		{"plugin", x, "plugin", plugin},
+		{"default", x, "", plugin},
+		{"miller-madow", x, "miller-madow", plugin + 2/(2*6*math.Ln2)},
+		{"chao-shen", x, "chao-shen", 1.8139237516537496},
+		{"james-stein", []float64{0, 1, 1, 2, 2, 2, 2, 2, 2, 2}, "james-stein", 1.3453924293549944},
+		{"james-stein full shrinkage", x, "james-stein", math.Log2(3)},
+	}
+
+	for _, tt := range tests {
+		if result := mRMR.Entropy(tt.data, tt.estimator); math.Abs(result-tt.expected) > 1e-12 {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, result)
+		}
+	}
+
+	// with plenty of samples the corrections vanish
+	r := rand.New(rand.NewSource(3))
+	uniform := make([]int, 20000)
+	for i := range uniform {
+		uniform[i] = r.Intn(8)
+	}
+	for _, estimator := range []string{"miller-madow", "chao-shen", "james-stein", "nsb"} {
+		if result := mRMR.Entropy(uniform, estimator); math.Abs(result-3) > 0.01 {
+			t.Errorf("%s: expected about 3 bits for a large uniform sample, got %v", estimator, result)
+		}
+	}
+}
+
+func TestMutualInfoEstimateBias(t *testing.T) {
+	// independent slices with many values and few samples
+	r := rand.New(rand.NewSource(5))
+	x := make([]int, 60)
+	y := make([]int, 60)
+	for i := range x {
+		x[i] = r.Intn(10)
+		y[i] = r.Intn(4)
+	}
+
+	plugin := mRMR.MutualInfoEstimate(x, y, "plugin")
+	if math.Abs(plugin-mRMR.MutualInfo(x, y)) > 1e-12 {
+		t.Errorf("Expected the plugin estimate to match MutualInfo, got %v", plugin)
+	}
+
+	for _, estimator := range []string{"miller-madow", "chao-shen", "james-stein", "nsb"} {
+		if result := mRMR.MutualInfoEstimate(x, y, estimator); result >= plugin {
+			t.Errorf("%s: expected less than the plugin estimate %v, got %v", estimator, plugin, result)
+		}
+	}
+}
+
+func TestEstimatorSelection(t *testing.T) {
+	data := generateDiscrete(300, 30)
+
+	for _, estimator := range []string{"miller-madow", "chao-shen", "james-stein", "nsb"} {
+		paras, err := mRMR.New(data,
+			mRMR.WithMethod(mRMR.MethodMIMI),
+			mRMR.WithEstimator(estimator),
+			mRMR.WithMaxFeatures(5),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		selectedFeatures, _, _ := paras.MRMR()
+		if len(selectedFeatures) != 5 {
+			t.Errorf("%s: expected 5 features, got %v", estimator, selectedFeatures)
+		}
+	}
+
+	if _, err := mRMR.New(data, mRMR.WithMethod(mRMR.MethodMIMI), mRMR.WithEstimator("grassberger")); err == nil {
+		t.Errorf("Expected an unknown estimator to be reported")
+	}
+	if _, err := mRMR.New(data, mRMR.WithMethod(mRMR.MethodFSPearson), mRMR.WithEstimator("nsb")); err == nil {
+		t.Errorf("Expected an estimator to be rejected for a method without mutual information")
+	}
+	if _, err := mRMR.New(data, mRMR.WithMethod(entropyMethod()), mRMR.WithEstimator("nsb")); err == nil {
+		t.Errorf("Expected an estimator to be rejected for a registered method")
+	}
+}
@@ -3,7 +3,7 @@ package main
 import (
 	"fmt"
 	"math/rand"
-	"src/mRMR"
+	"github.com/PQMark/mRMR"
 	"testing"
 	"math"
 )
@@ -155,4 +155,30 @@ func GenerateData(nSamples int) mRMR.DatamRMR {
 	}
 
 	return mRMR.DatamRMR{X: X, Class: class}
-}
\ No newline at end of file
+}
+
+func TestQuantizationLevel(t *testing.T) {
+	data := GenerateData(300)
+
+	// the first level at which every feature is within the threshold, tried one level at a time
+	firstLevel := func(threshold float64) int {
+		for level := 2; ; level++ {
+			_, quantized := mRMR.Discretization(data.X, level)
+
+			maxError := 0.0
+			for j := range data.X[0] {
+				maxError = math.Max(maxError, mRMR.QuantizationError(column(quantized, j), column(data.X, j)))
+			}
+
+			if maxError <= threshold || level >= len(data.X) {
+				return level
+			}
+		}
+	}
+
+	for _, threshold := range []float64{0.1, 0.01, 0.001} {
+		if expected, result := firstLevel(threshold), mRMR.QuantizationLevel(data.X, threshold); result != expected {
+			t.Errorf("Threshold %v: expected level %d, got %d", threshold, expected, result)
+		}
+	}
+}
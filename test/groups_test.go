@@ -0,0 +1,93 @@
+package main
+
+import (
+	"github.com/PQMark/mRMR"
+	"testing"
+)
+
+func TestJointGroups(t *testing.T) {
+	data := [][]float64{
+		{0, 1, 0},
+		{0, 0, 1},
+		{1, 1, 0},
+		{0, 1, 1},
+	}
+	groups := []int{7, 3, 7}
+
+	jointData, members := mRMR.JointGroups(data, groups)
+
+	expectedMembers := [][]int{{1}, {0, 2}}
+	// single-member groups keep their values
+	expectedJoint := [][]float64{
+		{1, 0},
+		{0, 1},
+		{1, 2},
+		{1, 1},
+	}
+
+	for g := range expectedMembers {
+		for i := range expectedMembers[g] {
+			if members[g][i] != expectedMembers[g][i] {
+				t.Fatalf("Expected members %v, got %v", expectedMembers, members)
+			}
+		}
+	}
+
+	for i := range expectedJoint {
+		for j := range expectedJoint[i] {
+			if jointData[i][j] != expectedJoint[i][j] {
+				t.Errorf("Joint encoding failed at row %d, group %d. Got %v, expected %v", i, j, jointData[i][j], expectedJoint[i][j])
+			}
+		}
+	}
+}
+
+func TestMRMRGroupCap(t *testing.T) {
+	data := GenerateData(2000)
+	groups := []int{0, 1, 0, 1, 2, 3}
+
+	paras := mRMR.ParasmRMR{
+		Data:           data,
+		Method:         "mi-mi",
+		Discretization: true,
+		BinSize:        10,
+		Groups:         groups,
+		GroupMode:      "cap",
+		MaxPerGroup:    1,
+	}
+
+	selectedFeatures, _, _ := paras.MRMR()
+
+	count := make(map[int]int)
+	for _, f := range selectedFeatures {
+		count[groups[f]]++
+		if count[groups[f]] > 1 {
+			t.Errorf("Group %d has more than one selected member: %v", groups[f], selectedFeatures)
+		}
+	}
+}
+
+func TestMRMRGroupJoint(t *testing.T) {
+	data := GenerateData(2000)
+	groups := []int{0, 1, 0, 1, 2, 3}
+
+	paras := mRMR.ParasmRMR{
+		Data:           data,
+		Method:         "mi-mi",
+		Discretization: true,
+		BinSize:        10,
+		Groups:         groups,
+		GroupMode:      "joint",
+		MaxFeatures:    1,
+	}
+
+	selectedFeatures, relevance, _ := paras.MRMR()
+
+	if len(selectedFeatures) != 2 || groups[selectedFeatures[0]] != groups[selectedFeatures[1]] {
+		t.Errorf("Expected both members of one group, got %v", selectedFeatures)
+	}
+
+	if relevance[0] != relevance[2] || relevance[1] != relevance[3] {
+		t.Errorf("Members of a group should share its relevance, got %v", relevance)
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"github.com/PQMark/mRMR"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestNormalizedHSIC(t *testing.T) {
+	x := []float64{0.1, 0.4, 0.35, 0.8, 1.2, 0.9}
+	y := []float64{1.0, 0.7, 0.9, 0.2, 0.1, 0.5}
+	class := []int{0, 0, 0, 1, 1, 1}
+
+	if result := mRMR.NormalizedHSIC(x, y); math.Abs(result-0.8432914571016894) > 1e-12 {
+		t.Errorf("Expected 0.8432914571016894, got %v", result)
+	}
+
+	if result := mRMR.NormalizedClassHSIC(x, class); math.Abs(result-0.9220852212340731) > 1e-12 {
+		t.Errorf("Expected 0.9220852212340731, got %v", result)
+	}
+
+	if result := mRMR.NormalizedHSIC(x, x); math.Abs(result-1) > 1e-12 {
+		t.Errorf("Expected 1 for identical slices, got %v", result)
+	}
+}
+
+func TestHSICApproximations(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	n := 400
+
+	x := make([]float64, n)
+	parabola := make([]float64, n)
+	noise := make([]float64, n)
+	class := make([]int, n)
+	for i := range x {
+		x[i] = r.Float64()*2 - 1
+		parabola[i] = x[i]*x[i] + 0.05*r.NormFloat64()
+		noise[i] = r.Float64()
+		if math.Abs(x[i]) > 0.5 {
+			class[i] = 1
+		}
+	}
+
+	exactDependent := mRMR.NormalizedHSIC(x, parabola)
+	exactIndependent := mRMR.NormalizedHSIC(x, noise)
+	exactRelevance := mRMR.NormalizedClassHSIC(x, class)
+
+	if exactDependent < 5*exactIndependent {
+		t.Fatalf("Expected the parabola to stand out from noise, got %v and %v", exactDependent, exactIndependent)
+	}
+
+	for _, approximation := range []string{"rff", "nystrom"} {
+		redundancy := mRMR.HSICRedundancy(approximation, 100, 1)
+		relevance := mRMR.HSICRelevance(approximation, 100, 1)
+
+		if result := redundancy(x, parabola); math.Abs(result-exactDependent) > 0.05 {
+			t.Errorf("%s: expected about %v, got %v", approximation, exactDependent, result)
+		}
+
+		if result := redundancy(x, noise); math.Abs(result-exactIndependent) > 0.05 {
+			t.Errorf("%s: expected about %v, got %v", approximation, exactIndependent, result)
+		}
+
+		if result := relevance(x, class); math.Abs(result-exactRelevance) > 0.05 {
+			t.Errorf("%s: expected relevance about %v, got %v", approximation, exactRelevance, result)
+		}
+	}
+}
+
+func TestHSICMethod(t *testing.T) {
+	paras := mRMR.ParasmRMR{
+		Data:        GenerateData(300),
+		Method:      mRMR.MethodHSICHSIC,
+		MaxFeatures: 2,
+	}
+
+	selectedFeatures, relevance, _ := paras.MRMR()
+	if len(selectedFeatures) != 2 {
+		t.Fatalf("Expected 2 features, got %v", selectedFeatures)
+	}
+
+	// features 0 and 1 determine the class, 4 and 5 are noise
+	if relevance[0] <= relevance[4] || relevance[1] <= relevance[5] {
+		t.Errorf("Expected informative features to be more relevant, got %v", relevance)
+	}
+}
+
+func TestHSICParameters(t *testing.T) {
+	data := GenerateData(300)
+
+	paras, err := mRMR.New(data, mRMR.WithMethod(mRMR.MethodHSICHSIC), mRMR.WithHSIC("rff", 50), mRMR.WithTieBreak("lowest-index", 7))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	custom := mRMR.ParasmRMR{
+		Data:           data,
+		Method:         mRMR.MethodHSICHSIC,
+		RelevanceFunc:  mRMR.HSICRelevance("rff", 50, 7),
+		RedundancyFunc: mRMR.HSICRedundancy("rff", 50, 7),
+	}
+
+	result, relevance, _ := paras.MRMR()
+	expected, expectedRelevance, _ := custom.MRMR()
+	if fmt.Sprint(result, relevance) != fmt.Sprint(expected, expectedRelevance) {
+		t.Errorf("Expected %v with relevance %v, got %v with %v", expected, expectedRelevance, result, relevance)
+	}
+
+	if _, err := mRMR.New(data, mRMR.WithMethod(mRMR.MethodHSICHSIC), mRMR.WithHSIC("sketch", 10)); err == nil {
+		t.Errorf("Expected an unknown approximation to be reported")
+	}
+	if _, err := mRMR.New(data, mRMR.WithMethod(mRMR.MethodFSPearson), mRMR.WithHSIC("nystrom", 10)); err == nil {
+		t.Errorf("Expected an HSIC approximation to be reported for another method")
+	}
+}
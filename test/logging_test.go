@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/PQMark/mRMR"
+	"log/slog"
+	"testing"
+)
+
+func TestMRMRLogger(t *testing.T) {
+	var buf bytes.Buffer
+
+	paras := mRMR.ParasmRMR{
+		Data:        generateDiscrete(200, 20),
+		Method:      "mi-mi",
+		MaxFeatures: 30,
+		Logger:      slog.New(slog.NewJSONHandler(&buf, nil)),
+	}
+
+	selectedFeatures, _, _ := paras.MRMR()
+
+	var warnings, selections []map[string]any
+	for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		var record map[string]any
+		if err := json.Unmarshal(line, &record); err != nil {
+			t.Fatalf("Invalid log record %q: %v", line, err)
+		}
+
+		switch record["msg"] {
+		case "maxFeatures exceeds number of features, adjusting":
+			warnings = append(warnings, record)
+		case "feature selected":
+			selections = append(selections, record)
+		case "scores":
+			t.Errorf("Debug records should be filtered at the default level: %v", record)
+		}
+	}
+
+	if len(warnings) != 1 || warnings[0]["level"] != "WARN" || warnings[0]["max_features"] != 30.0 {
+		t.Errorf("Expected one MaxFeatures warning, got %v", warnings)
+	}
+
+	if len(selections) != len(selectedFeatures) {
+		t.Fatalf("Expected %d selection records, got %d", len(selectedFeatures), len(selections))
+	}
+
+	for i, record := range selections {
+		if record["step"] != float64(i+1) || record["feature"] != float64(selectedFeatures[i]) {
+			t.Errorf("Unexpected record for step %d: %v", i+1, record)
+		}
+
+		for _, key := range []string{"score", "candidates", "step_time", "elapsed"} {
+			if _, ok := record[key]; !ok {
+				t.Errorf("Record for step %d has no %q: %v", i+1, key, record)
+			}
+		}
+	}
+}
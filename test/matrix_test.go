@@ -0,0 +1,249 @@
+package main
+
+import (
+	"compress/gzip"
+	"fmt"
+	"github.com/PQMark/mRMR"
+	"io"
+	"os"
+	"testing"
+)
+
+func TestMatrixFloat32(t *testing.T) {
+	data := GenerateData(300)
+
+	// the float64 reference holds exactly the float32 values
+	rows := make(mRMR.Dense[float32], len(data.X))
+	widened := make([][]float64, len(data.X))
+	for i, row := range data.X {
+		rows[i] = make([]float32, len(row))
+		widened[i] = make([]float64, len(row))
+		for j, val := range row {
+			rows[i][j] = float32(val)
+			widened[i][j] = float64(rows[i][j])
+		}
+	}
+
+	tests := []struct {
+		method         mRMR.Method
+		discretization bool
+		relevanceFunc  func([]float64, []int) float64
+	}{
+		{mRMR.MethodMIMI, true, nil},
+		{mRMR.MethodFSPearson, false, nil},
+		{mRMR.MethodFSSpearman, false, nil},
+		{mRMR.MethodFSKendall, false, nil},
+		{mRMR.MethodFSDcor, false, nil},
+		{mRMR.MethodNMINMI, false, nil},
+		{mRMR.MethodFSPearson, false, mRMR.KruskalWallis},
+		{mRMR.MethodMICMIC, false, nil},
+	}
+
+	for _, tt := range tests {
+		dense := mRMR.ParasmRMR{
+			Data:           mRMR.DatamRMR{X: widened, Class: data.Class},
+			Method:         tt.method,
+			Discretization: tt.discretization,
+			RelevanceFunc:  tt.relevanceFunc,
+			MaxFeatures:    4,
+		}
+		native := dense
+		native.Data = mRMR.DatamRMR{Matrix: rows, Class: data.Class}
+
+		denseFeatures, denseRelevance, denseRedundancy := dense.MRMR()
+		nativeFeatures, nativeRelevance, nativeRedundancy := native.MRMR()
+
+		if fmt.Sprint(nativeFeatures) != fmt.Sprint(denseFeatures) {
+			t.Errorf("%s: expected features %v, got %v", tt.method, denseFeatures, nativeFeatures)
+		}
+		if fmt.Sprint(nativeRelevance) != fmt.Sprint(denseRelevance) {
+			t.Errorf("%s: expected relevance %v, got %v", tt.method, denseRelevance, nativeRelevance)
+		}
+		if fmt.Sprint(nativeRedundancy) != fmt.Sprint(denseRedundancy) {
+			t.Errorf("%s: expected redundancy %v, got %v", tt.method, denseRedundancy, nativeRedundancy)
+		}
+	}
+}
+
+func TestMatrixBinned(t *testing.T) {
+	data := GenerateData(400)
+	for _, row := range data.X {
+		row[3] = 2.5 // a constant column is one bin
+	}
+
+	// up to 128 bins fit int8 codes, more need int16
+	for _, binSize := range []int{10, 300} {
+		for _, method := range []mRMR.Method{mRMR.MethodMIMI, mRMR.MethodFSPearson} {
+			dense := mRMR.ParasmRMR{Data: data, Method: method, Discretization: true, BinSize: binSize}
+			native := dense
+			native.Data = mRMR.DatamRMR{Matrix: mRMR.NewFlat(data.X, false), Class: data.Class}
+
+			denseFeatures, denseRelevance, denseRedundancy := dense.MRMR()
+			nativeFeatures, nativeRelevance, nativeRedundancy := native.MRMR()
+
+			if fmt.Sprint(nativeFeatures, nativeRelevance, nativeRedundancy) != fmt.Sprint(denseFeatures, denseRelevance, denseRedundancy) {
+				t.Errorf("%s with %d bins: expected %v %v, got %v %v", method, binSize, denseFeatures, denseRelevance, nativeFeatures, nativeRelevance)
+			}
+		}
+	}
+}
+
+func TestMatrixIntegerCoded(t *testing.T) {
+	data := generateDiscrete(200, 20)
+
+	codes := make(mRMR.Dense[int8], len(data.X))
+	for i, row := range data.X {
+		codes[i] = make([]int8, len(row))
+		for j, val := range row {
+			codes[i][j] = int8(val)
+		}
+	}
+
+	for _, method := range []mRMR.Method{mRMR.MethodMIMI, mRMR.MethodFSKendall} {
+		dense := mRMR.ParasmRMR{Data: data, Method: method, MaxFeatures: 6}
+		native := mRMR.ParasmRMR{Data: mRMR.DatamRMR{Matrix: codes, Class: data.Class}, Method: method, MaxFeatures: 6}
+
+		denseFeatures, _, _ := dense.MRMR()
+		if nativeFeatures, _, _ := native.MRMR(); fmt.Sprint(nativeFeatures) != fmt.Sprint(denseFeatures) {
+			t.Errorf("%s: expected features %v, got %v", method, denseFeatures, nativeFeatures)
+		}
+	}
+}
+
+func TestMatrixValidate(t *testing.T) {
+	data := generateDiscrete(10, 3)
+	ragged := mRMR.Dense[float32]{{1, 2, 3}, {1, 2}}
+
+	if _, err := mRMR.New(mRMR.DatamRMR{Matrix: ragged, Class: []int{0, 1}}); err == nil {
+		t.Errorf("Expected a ragged matrix to be reported")
+	}
+	if _, err := mRMR.New(mRMR.DatamRMR{X: data.X, Matrix: mRMR.Dense[float64](data.X), Class: data.Class}); err == nil {
+		t.Errorf("Expected X and Matrix together to be reported")
+	}
+}
+
+func TestFlat(t *testing.T) {
+	data := GenerateData(200)
+
+	for _, colMajor := range []bool{false, true} {
+		flat := mRMR.NewFlat(data.X, colMajor)
+
+		for j := 0; j < flat.Cols; j++ {
+			col := flat.Column(j, nil)
+			for i, val := range col {
+				if val != data.X[i][j] || flat.At(i, j) != data.X[i][j] {
+					t.Fatalf("colMajor %v: value mismatch at row %d, column %d", colMajor, i, j)
+				}
+			}
+		}
+
+		for _, method := range []mRMR.Method{mRMR.MethodMIMI, mRMR.MethodFSPearson, mRMR.MethodNMINMI} {
+			dense := mRMR.ParasmRMR{Data: data, Method: method, MaxFeatures: 4}
+			native := mRMR.ParasmRMR{Data: mRMR.DatamRMR{Matrix: flat, Class: data.Class}, Method: method, MaxFeatures: 4}
+
+			denseFeatures, denseRelevance, _ := dense.MRMR()
+			nativeFeatures, nativeRelevance, _ := native.MRMR()
+			if fmt.Sprint(nativeFeatures) != fmt.Sprint(denseFeatures) || fmt.Sprint(nativeRelevance) != fmt.Sprint(denseRelevance) {
+				t.Errorf("colMajor %v, %s: expected %v, got %v", colMajor, method, denseFeatures, nativeFeatures)
+			}
+		}
+	}
+
+	// column views of a column-major matrix, and reused buffers of a row-major one, allocate nothing
+	colMajor := mRMR.NewFlat(data.X, true)
+	if allocs := testing.AllocsPerRun(100, func() { colMajor.Column(3, nil) }); allocs != 0 {
+		t.Errorf("Expected column views without allocation, got %v allocations", allocs)
+	}
+
+	rowMajor := mRMR.NewFlat(data.X, false)
+	buf := make([]float64, rowMajor.Rows)
+	if allocs := testing.AllocsPerRun(100, func() { buf = rowMajor.Column(3, buf) }); allocs != 0 {
+		t.Errorf("Expected a reused buffer without allocation, got %v allocations", allocs)
+	}
+}
+
+func TestFlatStride(t *testing.T) {
+	// two instances of three features, each row padded to a stride of four
+	flat := mRMR.Flat[float32]{Data: []float32{1, 2, 3, -1, 4, 5, 6, -1}, Rows: 2, Cols: 3, Stride: 4}
+
+	if col := flat.Column(2, nil); fmt.Sprint(col) != "[3 6]" {
+		t.Errorf("Expected column [3 6], got %v", col)
+	}
+
+	flat.ColMajor = true
+	flat.Rows, flat.Cols = 3, 2
+	if col := flat.Column(1, nil); fmt.Sprint(col) != "[4 5 6]" {
+		t.Errorf("Expected column [4 5 6], got %v", col)
+	}
+
+	short := mRMR.Flat[float64]{Data: make([]float64, 5), Rows: 2, Cols: 3}
+	if _, err := mRMR.New(mRMR.DatamRMR{Matrix: short, Class: []int{0, 1}}); err == nil {
+		t.Errorf("Expected too short data to be reported")
+	}
+}
+
+// BenchmarkMNIST compares selection on row slices with a column-major Flat on 1,000 MNIST test images.
+func BenchmarkMNIST(b *testing.B) {
+	X, class := readMNIST(b, 1000)
+
+	inputs := []struct {
+		name string
+		data mRMR.DatamRMR
+	}{
+		{"rows", mRMR.DatamRMR{X: X, Class: class}},
+		{"flat", mRMR.DatamRMR{Matrix: mRMR.NewFlat(X, true), Class: class}},
+		{"flat-rows", mRMR.DatamRMR{Matrix: mRMR.NewFlat(X, false), Class: class}},
+	}
+
+	for _, method := range []mRMR.Method{mRMR.MethodFSPearson, mRMR.MethodMIMI} {
+		for _, input := range inputs {
+			b.Run(fmt.Sprintf("%s/%s", method, input.name), func(b *testing.B) {
+				b.ReportAllocs()
+
+				for i := 0; i < b.N; i++ {
+					paras := mRMR.ParasmRMR{Data: input.data, Method: method, MaxFeatures: 20}
+					paras.MRMR()
+				}
+			})
+		}
+	}
+}
+
+// readMNIST reads the first n images of the MNIST test set in examples, one pixel per feature.
+func readMNIST(tb testing.TB, n int) ([][]float64, []int) {
+	images := readGzip(tb, "../examples/t10k-images-idx3-ubyte.gz")
+	labels := readGzip(tb, "../examples/t10k-labels-idx1-ubyte.gz")
+
+	const pixels = 28 * 28
+	X := make([][]float64, n)
+	class := make([]int, n)
+	for i := range X {
+		X[i] = make([]float64, pixels)
+		for j := range X[i] {
+			X[i][j] = float64(images[16+i*pixels+j])
+		}
+		class[i] = int(labels[8+i])
+	}
+
+	return X, class
+}
+
+func readGzip(tb testing.TB, path string) []byte {
+	f, err := os.Open(path)
+	if err != nil {
+		tb.Skip("MNIST data not available:", err)
+	}
+	defer f.Close()
+
+	r, err := gzip.NewReader(f)
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	return data
+}
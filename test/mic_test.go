@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"github.com/PQMark/mRMR"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestMIC(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	n := 1000
+
+	x := make([]float64, n)
+	parabola := make([]float64, n)
+	sine := make([]float64, n)
+	noise := make([]float64, n)
+	for i := range x {
+		x[i] = r.Float64()*2 - 1
+		parabola[i] = x[i] * x[i]
+		sine[i] = math.Sin(6 * x[i])
+		noise[i] = r.Float64()
+	}
+
+	tests := []struct {
+		name      string
+		data1     []float64
+		data2     []float64
+		low, high float64
+	}{
+		// best 2x2 grid puts one point in a pure column: 1 - 3/4*H(1/3, 2/3)
+		{"four points", []float64{1, 2, 3, 4}, []float64{1, 2, 1, 2}, 0.31127812445913283, 0.31127812445913283},
+		{"identity", x, x, 1, 1},
+		{"parabola", x, parabola, 1, 1},
+		{"sine", x, sine, 1, 1},
+		{"independent", x, noise, 0, 0.2},
+	}
+
+	for _, tt := range tests {
+		result := mRMR.MIC(tt.data1, tt.data2, 0.6, 15)
+		if result < tt.low-1e-12 || result > tt.high+1e-12 {
+			t.Errorf("%s: expected MIC in [%v, %v], got %v", tt.name, tt.low, tt.high, result)
+		}
+
+		if reverse := mRMR.MIC(tt.data2, tt.data1, 0.6, 15); reverse != result {
+			t.Errorf("%s: MIC is not symmetric: %v and %v", tt.name, result, reverse)
+		}
+	}
+}
+
+func TestMICRelevance(t *testing.T) {
+	data := GenerateData(300)
+	relevance := mRMR.Relevance(data.X, data.Class, mRMR.MICRelevance(0.6, 15))
+
+	// features 0 and 1 determine the class, 4 and 5 are noise
+	for _, f := range []int{0, 1} {
+		for _, noisy := range []int{4, 5} {
+			if relevance[f] <= relevance[noisy] {
+				t.Errorf("Expected feature %d to be more relevant than %d, got %v", f, noisy, relevance)
+			}
+		}
+	}
+
+	paras := mRMR.ParasmRMR{Data: data, Method: mRMR.MethodMICMIC, MaxFeatures: 2}
+	if selectedFeatures, _, _ := paras.MRMR(); len(selectedFeatures) != 2 {
+		t.Errorf("Expected 2 features, got %v", selectedFeatures)
+	}
+}
+
+func TestMICParameters(t *testing.T) {
+	data := GenerateData(200)
+
+	paras, err := mRMR.New(data, mRMR.WithMethod(mRMR.MethodMICMIC), mRMR.WithMIC(0.55, 5))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	custom := mRMR.ParasmRMR{
+		Data:           data,
+		Method:         mRMR.MethodMICMIC,
+		RelevanceFunc:  mRMR.MICRelevance(0.55, 5),
+		RedundancyFunc: mRMR.MICRedundancy(0.55, 5),
+	}
+
+	result, relevance, _ := paras.MRMR()
+	expected, expectedRelevance, _ := custom.MRMR()
+	if fmt.Sprint(result, relevance) != fmt.Sprint(expected, expectedRelevance) {
+		t.Errorf("Expected %v with relevance %v, got %v with %v", expected, expectedRelevance, result, relevance)
+	}
+
+	if _, err := mRMR.New(data, mRMR.WithMethod(mRMR.MethodMICMIC), mRMR.WithMIC(1.5, 15)); err == nil {
+		t.Errorf("Expected alpha above 1 to be reported")
+	}
+	if _, err := mRMR.New(data, mRMR.WithMethod(mRMR.MethodMIMI), mRMR.WithMIC(0.6, 15)); err == nil {
+		t.Errorf("Expected MIC parameters to be reported for another method")
+	}
+}
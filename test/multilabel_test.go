@@ -0,0 +1,104 @@
+package main
+
+import (
+	"github.com/PQMark/mRMR"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestLabelPowerset(t *testing.T) {
+	labels := [][]int{
+		{0, 1},
+		{1, 1},
+		{0, 1},
+		{1, 0},
+	}
+	expected := []int{0, 1, 0, 2}
+
+	result := mRMR.LabelPowerset(labels)
+
+	for i := range expected {
+		if result[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected, result)
+			break
+		}
+	}
+}
+
+func TestAggregateRelevance(t *testing.T) {
+	const epsilon = 1e-6
+
+	perLabel := [][]float64{
+		{0.2, 0.8, 0.0, -0.3},
+		{0.6, 0.4, 0.1, -0.1},
+	}
+
+	testCases := []struct {
+		aggregation string
+		expected    []float64
+	}{
+		{
+			aggregation: "mean",
+			expected:    []float64{0.4, 0.6, 0.05, -0.2},
+		},
+		{
+			aggregation: "max",
+			expected:    []float64{0.6, 0.8, 0.1, -0.1},
+		},
+	}
+
+	for _, tt := range testCases {
+		result := mRMR.AggregateRelevance(perLabel, tt.aggregation)
+
+		for i := range result {
+			if math.Abs(result[i]-tt.expected[i]) > epsilon {
+				t.Errorf("%s: expected %v, got %v", tt.aggregation, tt.expected, result)
+				break
+			}
+		}
+	}
+}
+
+func TestMRMRMultiLabel(t *testing.T) {
+	r := rand.New(rand.NewSource(66))
+	nSamples := 1000
+
+	X := make([][]float64, nSamples)
+	labels := make([][]int, nSamples)
+
+	for i := range X {
+		X[i] = []float64{r.Float64(), r.Float64(), r.Float64(), r.Float64()}
+		labels[i] = make([]int, 2)
+
+		// each label depends on one feature
+		if X[i][0] > 0.5 {
+			labels[i][0] = 1
+		}
+		if X[i][2] > 0.5 {
+			labels[i][1] = 1
+		}
+	}
+
+	for _, aggregation := range []string{"mean", "max", "powerset"} {
+		paras := mRMR.ParasmRMR{
+			Data:             mRMR.DatamRMR{X: X, Labels: labels},
+			Method:           "mi-mi",
+			Discretization:   true,
+			BinSize:          10,
+			MaxFeatures:      2,
+			LabelAggregation: aggregation,
+		}
+
+		selectedFeatures, _, _ := paras.MRMR()
+
+		got := map[int]bool{}
+		for _, f := range selectedFeatures {
+			got[f] = true
+		}
+
+		if len(selectedFeatures) != 2 || !got[0] || !got[2] {
+			t.Errorf("%s: expected features 0 and 2, got %v", aggregation, selectedFeatures)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"github.com/PQMark/mRMR"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestMultivariateIdentities(t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	n := 500
+	x := make([]float64, n)
+	y := make([]float64, n)
+	z := make([]float64, n)
+	for i := range x {
+		z[i] = float64(r.Intn(3))
+		x[i] = float64((int(z[i]) + r.Intn(2)) % 4)
+		y[i] = float64((int(x[i]) + r.Intn(3)) % 5)
+	}
+	constant := make([]float64, n)
+
+	hx := mRMR.Entropy(x, "")
+	hxy := mRMR.JointEntropy(x, y)
+	hxz := mRMR.JointEntropy(x, z)
+	hyz := mRMR.JointEntropy(y, z)
+	hxyz := mRMR.JointEntropy(x, y, z)
+	hz := mRMR.JointEntropy(z)
+
+	tests := []struct {
+		name     string
+		result   float64
+		expected float64
+	}{
+		{"joint entropy of one slice", mRMR.JointEntropy(x), hx},
+		{"joint entropy of two slices", hxy, hx + mRMR.Entropy(y, "") - mRMR.MutualInfo(x, y)},
+		{"joint entropy ignores repeats", mRMR.JointEntropy(x, y, x), hxy},
+		{"conditional entropy", mRMR.ConditionalEntropy(x, z), hxz - hz},
+		{"conditioning on a constant", mRMR.ConditionalEntropy(x, constant), hx},
+		{"chain rule of entropy", hz + mRMR.ConditionalEntropy(x, z) + mRMR.ConditionalEntropy(y, joinCodes(x, z)), hxyz},
+		{"conditional mutual information", mRMR.ConditionalMutualInfo(x, y, z), hxz + hyz - hxyz - hz},
+		{"chain rule of mutual information", mRMR.ConditionalMutualInfo(x, y, z), mRMR.MutualInfo(x, joinCodes(y, z)) - mRMR.MutualInfo(x, z)},
+		{"conditional mutual information given a constant", mRMR.ConditionalMutualInfo(x, y, constant), mRMR.MutualInfo(x, y)},
+		{"interaction of one slice", mRMR.InteractionInformation(x), hx},
+		{"interaction of two slices", mRMR.InteractionInformation(x, y), mRMR.MutualInfo(x, y)},
+		{"interaction of three slices", mRMR.InteractionInformation(x, y, z), mRMR.MutualInfo(x, y) - mRMR.ConditionalMutualInfo(x, y, z)},
+		{"interaction of copies", mRMR.InteractionInformation(x, x, x), hx},
+	}
+
+	for _, tt := range tests {
+		if math.Abs(tt.result-tt.expected) > 1e-9 {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, tt.result)
+		}
+	}
+}
+
+func TestXORSynergy(t *testing.T) {
+	var x, y []int
+	var z []float32
+	for rep := 0; rep < 25; rep++ {
+		for a := 0; a < 2; a++ {
+			for b := 0; b < 2; b++ {
+				x = append(x, a)
+				y = append(y, b)
+				z = append(z, float32(a^b))
+			}
+		}
+	}
+
+	if mi := mRMR.MutualInfo(x, y); math.Abs(mi) > 1e-12 {
+		t.Errorf("Expected independent inputs, got I(X;Y) = %v", mi)
+	}
+	if cmi := mRMR.ConditionalMutualInfo(x, y, z); math.Abs(cmi-1) > 1e-12 {
+		t.Errorf("Expected I(X;Y|Z) = 1, got %v", cmi)
+	}
+	if h := mRMR.ConditionalEntropy(z, x); math.Abs(h-1) > 1e-12 {
+		t.Errorf("Expected H(Z|X) = 1, got %v", h)
+	}
+
+	zInt := make([]int, len(z))
+	for i, val := range z {
+		zInt[i] = int(val)
+	}
+	if ii := mRMR.InteractionInformation(x, y, zInt); math.Abs(ii+1) > 1e-12 {
+		t.Errorf("Expected interaction information -1, got %v", ii)
+	}
+}
+
+// joinCodes codes the pairs of two discrete slices as one slice.
+func joinCodes(a, b []float64) []float64 {
+	joint := make([]float64, len(a))
+	for i := range a {
+		joint[i] = a[i]*1000 + b[i]
+	}
+
+	return joint
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"github.com/PQMark/mRMR"
+	"math"
+	"testing"
+)
+
+func TestNormalizedMutualInfo(t *testing.T) {
+	x := []float64{0, 0, 1, 1, 2, 2, 2, 0}
+	y := []float64{0, 0, 1, 1, 1, 1, 0, 0}
+
+	tests := []struct {
+		normalization string
+		expected      float64
+	}{
+		{"su", 0.5119624112418625},
+		{"min", 0.6556390622295665},
+		{"sqrt", 0.52471645413335},
+		{"iqr", 0.3440520690536641},
+	}
+
+	for _, tt := range tests {
+		if result := mRMR.NormalizedMutualInfo(x, y, tt.normalization); math.Abs(result-tt.expected) > 1e-12 {
+			t.Errorf("%s: expected %v, got %v", tt.normalization, tt.expected, result)
+		}
+
+		// identical slices are fully dependent, a constant carries no information
+		if result := mRMR.NormalizedMutualInfo(x, x, tt.normalization); math.Abs(result-1) > 1e-12 {
+			t.Errorf("%s: expected 1 for identical slices, got %v", tt.normalization, result)
+		}
+		if result := mRMR.NormalizedMutualInfo(x, make([]float64, len(x)), tt.normalization); result != 0 {
+			t.Errorf("%s: expected 0 against a constant, got %v", tt.normalization, result)
+		}
+	}
+
+	if su := mRMR.SymmetricUncertainty(x, y); su != mRMR.NormalizedMutualInfo(x, y, "su") {
+		t.Errorf("Expected SymmetricUncertainty to match 'su', got %v", su)
+	}
+}
+
+func TestMINormalization(t *testing.T) {
+	data := generateDiscrete(300, 30)
+
+	for _, normalization := range []string{"su", "min", "sqrt", "iqr"} {
+		paras, err := mRMR.New(data,
+			mRMR.WithMethod(mRMR.MethodMIMI),
+			mRMR.WithMINormalization(normalization),
+			mRMR.WithMaxFeatures(8),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		selectedFeatures, relevance, redundancy := paras.MRMR()
+
+		for f, val := range relevance {
+			if val < 0 || val > 1 {
+				t.Errorf("%s: relevance of feature %d out of [0, 1]: %v", normalization, f, val)
+			}
+		}
+		for key, val := range redundancy {
+			if val < 0 || val > 1 {
+				t.Errorf("%s: redundancy %v out of [0, 1]: %v", normalization, key, val)
+			}
+		}
+
+		// the sparse backend gives the same selection
+		sparse := mRMR.ParasmRMR{
+			Data:            mRMR.DatamRMR{Sparse: mRMR.SparseFromDense(data.X), Class: data.Class},
+			Method:          mRMR.MethodMIMI,
+			MINormalization: normalization,
+			MaxFeatures:     8,
+		}
+		if sparseFeatures, _, _ := sparse.MRMR(); fmt.Sprint(sparseFeatures) != fmt.Sprint(selectedFeatures) {
+			t.Errorf("%s: sparse selection %v differs from dense %v", normalization, sparseFeatures, selectedFeatures)
+		}
+	}
+
+	if _, err := mRMR.New(data, mRMR.WithMethod(mRMR.MethodFSPearson), mRMR.WithMINormalization("su")); err == nil {
+		t.Errorf("Expected MINormalization to be rejected for a method without mutual information")
+	}
+
+	// a registered information method keeps its own measures, so the normalization cannot apply to it
+	if _, err := mRMR.New(data, mRMR.WithMethod(entropyMethod()), mRMR.WithMINormalization("su")); err == nil {
+		t.Errorf("Expected MINormalization to be rejected for a registered method")
+	}
+}
+
+// entropyMethod registers, once, an information method with measures of its own.
+func entropyMethod() mRMR.Method {
+	if _, ok := mRMR.LookupMethod("entropy-mi"); !ok {
+		mRMR.RegisterMethod("entropy-mi", mRMR.MethodSpec{
+			Relevance:   func(feature []float64, class []int) float64 { return mRMR.Entropy(feature, "") },
+			Redundancy:  mRMR.MutualInfo[float64, float64],
+			Information: true,
+		})
+	}
+
+	return "entropy-mi"
+}
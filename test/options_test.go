@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"github.com/PQMark/mRMR"
+	"strings"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	data := generateDiscrete(200, 30)
+
+	paras, err := mRMR.New(data,
+		mRMR.WithMethod(mRMR.MethodMIMI),
+		mRMR.WithRedundancyMethod(mRMR.RedundancyMax),
+		mRMR.WithMaxFeatures(8),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := mRMR.ParasmRMR{
+		Data:             data,
+		Method:           "mi-mi",
+		RedundancyMethod: "max",
+		MaxFeatures:      8,
+	}
+
+	result, _, _ := paras.MRMR()
+	want, _, _ := expected.MRMR()
+
+	if fmt.Sprint(result) != fmt.Sprint(want) {
+		t.Errorf("Expected %v, got %v", want, result)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	data := generateDiscrete(50, 10)
+
+	_, err := mRMR.New(data,
+		mRMR.WithMethod("no-such-method"),
+		mRMR.WithCalculation("sum"),
+		mRMR.WithMaxFeatures(-1),
+		mRMR.WithBudget(3),
+	)
+	if err == nil {
+		t.Fatal("Expected validation errors")
+	}
+
+	// every problem is reported, not just the first
+	for _, problem := range []string{"no-such-method", "sum", "MaxFeatures", "Budget"} {
+		if !strings.Contains(err.Error(), problem) {
+			t.Errorf("Expected %q to be reported, got %v", problem, err)
+		}
+	}
+
+	if _, err := mRMR.New(mRMR.DatamRMR{}); err == nil || !strings.Contains(err.Error(), "no data") {
+		t.Errorf("Expected missing data to be reported, got %v", err)
+	}
+}
+
+func TestRunKeepsParameters(t *testing.T) {
+	paras := mRMR.ParasmRMR{Data: generateDiscrete(100, 10)}
+	paras.MRMR()
+
+	if paras.Method != "" || paras.Threshold != 0 || paras.MaxFeatures != 0 || paras.RelevanceFunc != nil {
+		t.Errorf("Expected the caller's parameters to be left unset, got %+v", paras)
+	}
+}
+
+func TestZeroThreshold(t *testing.T) {
+	paras, err := mRMR.New(generateDiscrete(50, 5), mRMR.WithThreshold(0), mRMR.WithMaxFeatures(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result, _, _ := paras.MRMR(); len(result) == 0 {
+		t.Errorf("Expected features to be selected with a zero threshold")
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"github.com/PQMark/mRMR"
+	"testing"
+	"time"
+)
+
+func TestMRMRContextCancel(t *testing.T) {
+	data := generateDiscrete(200, 40)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var events []mRMR.ProgressEvent
+
+	paras := mRMR.ParasmRMR{
+		Data:        data,
+		Method:      "mi-mi",
+		MaxFeatures: 20,
+		Progress: func(event mRMR.ProgressEvent) {
+			events = append(events, event)
+			if event.Step == 3 {
+				cancel()
+			}
+		},
+	}
+
+	selectedFeatures, relevance, _, err := paras.MRMRContext(ctx)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+
+	if len(selectedFeatures) != 3 || len(relevance) != 40 {
+		t.Errorf("Expected the 3 features selected before cancelling and all relevance scores, got %v and %d scores", selectedFeatures, len(relevance))
+	}
+
+	if len(events) != 4 || events[0].Stage != "relevance" || events[0].Feature != -1 {
+		t.Fatalf("Expected a relevance event then 3 selection events, got %+v", events)
+	}
+
+	for i, event := range events[1:] {
+		if event.Stage != "selection" || event.Step != i+1 || event.Feature != selectedFeatures[i] || event.Elapsed < event.StepTime {
+			t.Errorf("Unexpected event for step %d: %+v", i+1, event)
+		}
+	}
+}
+
+func TestMRMRTimeout(t *testing.T) {
+	paras := mRMR.ParasmRMR{
+		Data:    generateDiscrete(200, 40),
+		Method:  "mi-mi",
+		Timeout: time.Nanosecond,
+	}
+
+	_, _, _, err := paras.MRMRContext(context.Background())
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/PQMark/mRMR"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+func TestLazyPruning(t *testing.T) {
+	data := generateDiscrete(300, 60)
+	continuous := GenerateData(300)
+
+	costs := make([]float64, 60)
+	groups := make([]int, 60)
+	for j := range costs {
+		costs[j] = 1 + float64(j%7)/3
+		groups[j] = j / 5
+	}
+
+	tests := []struct {
+		name  string
+		paras mRMR.ParasmRMR
+	}{
+		{"mi-mi avg", mRMR.ParasmRMR{Data: data, Method: "mi-mi", MaxFeatures: 15}},
+		{"mi-mi max", mRMR.ParasmRMR{Data: data, Method: "mi-mi", RedundancyMethod: "max", MaxFeatures: 15}},
+		{"mi-mi quo", mRMR.ParasmRMR{Data: data, Method: "mi-mi", Calculation: "quo", MaxFeatures: 15}},
+		{"nmi-nmi", mRMR.ParasmRMR{Data: data, Method: "nmi-nmi", MaxFeatures: 15}},
+		{"normalized estimator", mRMR.ParasmRMR{Data: data, Method: "mi-mi", Estimator: "james-stein", MINormalization: "su", MaxFeatures: 15}},
+		{"fs-pearson", mRMR.ParasmRMR{Data: continuous, Method: "fs-pearson"}},
+		{"penalty costs", mRMR.ParasmRMR{Data: data, Method: "mi-mi", Costs: costs, CostLambda: 0.01, MaxFeatures: 15}},
+		{"ratio costs and budget", mRMR.ParasmRMR{Data: data, Method: "mi-mi", Costs: costs, CostMode: "ratio", Budget: 20}},
+		{"group cap", mRMR.ParasmRMR{Data: data, Method: "mi-mi", Groups: groups, GroupMode: "cap", MaxFeatures: 10}},
+		{"random ties", mRMR.ParasmRMR{Data: duplicateData(), Method: "mi-mi", TieBreak: "random", Seed: 3}},
+		{"relevance ties", mRMR.ParasmRMR{Data: duplicateData(), Method: "mi-mi", TieBreak: "relevance"}},
+	}
+
+	for _, tt := range tests {
+		lazy := tt.paras
+		lazy.Prune = "lazy"
+
+		expected, expectedRelevance, expectedRedundancy := tt.paras.MRMR()
+		selected, relevance, redundancy := lazy.MRMR()
+
+		if fmt.Sprint(selected) != fmt.Sprint(expected) || fmt.Sprint(relevance) != fmt.Sprint(expectedRelevance) {
+			t.Errorf("%s: expected %v, got %v", tt.name, expected, selected)
+		}
+
+		// lazy redundancy is a subset of the exact values
+		for key, val := range redundancy {
+			if expectedRedundancy[key] != val {
+				t.Errorf("%s: redundancy %v is %v, expected %v", tt.name, key, val, expectedRedundancy[key])
+				break
+			}
+		}
+		if len(redundancy) > len(expectedRedundancy) {
+			t.Errorf("%s: expected at most %d redundancy values, got %d", tt.name, len(expectedRedundancy), len(redundancy))
+		}
+	}
+
+	// with many candidates most of them are never scored
+	exact := mRMR.ParasmRMR{Data: generateDiscrete(300, 400), Method: "mi-mi", MaxFeatures: 10}
+	lazy := exact
+	lazy.Prune = "lazy"
+
+	_, _, exactRedundancy := exact.MRMR()
+	if _, _, redundancy := lazy.MRMR(); len(redundancy) >= len(exactRedundancy)/2 {
+		t.Errorf("Expected lazy pruning to skip most redundancy, got %d of %d values", len(redundancy), len(exactRedundancy))
+	}
+}
+
+func TestTopPruning(t *testing.T) {
+	data := generateDiscrete(300, 60)
+
+	full := mRMR.ParasmRMR{Data: data, Method: "mi-mi", MaxFeatures: 8}
+	expected, _, _ := full.MRMR()
+
+	all := full
+	all.Prune, all.PruneCandidates = "top", 60
+	if selected, _, _ := all.MRMR(); fmt.Sprint(selected) != fmt.Sprint(expected) {
+		t.Errorf("Expected keeping every candidate to change nothing, got %v instead of %v", selected, expected)
+	}
+
+	top := full
+	top.Prune, top.PruneCandidates = "top", 12
+	selected, relevance, _ := top.MRMR()
+
+	order := make([]int, len(relevance))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return relevance[order[a]] > relevance[order[b]] })
+
+	kept := make(map[int]bool)
+	for _, f := range order[:12] {
+		kept[f] = true
+	}
+	for _, f := range selected {
+		if !kept[f] {
+			t.Errorf("Expected only the 12 most relevant features, got %d in %v", f, selected)
+		}
+	}
+
+	if _, err := mRMR.New(data, mRMR.WithPruning("greedy", 0)); err == nil {
+		t.Errorf("Expected an unknown pruning to be reported")
+	}
+	if _, err := mRMR.New(data, mRMR.WithPruning("top", -1)); err == nil {
+		t.Errorf("Expected negative PruneCandidates to be reported")
+	}
+
+	// fewer candidates than features to select are raised to MaxFeatures
+	few := full
+	few.Prune, few.PruneCandidates = "top", 3
+	if selected, _, _ := few.MRMR(); len(selected) != 8 {
+		t.Errorf("Expected 8 features with 3 candidates, got %v", selected)
+	}
+}
+
+func TestLazyPruningSignedRedundancy(t *testing.T) {
+	data := generateDiscrete(100, 10)
+	signed := func(data1, data2 []float64) float64 { return mRMR.MutualInfo(data1, data2) - 0.1 }
+
+	// bias-corrected mutual information and custom measures can be negative, which breaks the bounds
+	if _, err := mRMR.New(data, mRMR.WithMethod(mRMR.MethodMIMI), mRMR.WithEstimator("james-stein"), mRMR.WithPruning("lazy", 0)); err == nil {
+		t.Errorf("Expected lazy pruning to be rejected with a bias-corrected estimator")
+	}
+	if _, err := mRMR.New(data, mRMR.WithMethod(mRMR.MethodMIMI), mRMR.WithPruning("lazy", 0), mRMR.WithRedundancyFunc(signed)); err == nil {
+		t.Errorf("Expected lazy pruning to be rejected with a custom redundancy")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected a panic for lazy pruning with a custom redundancy")
+		}
+	}()
+	paras := mRMR.ParasmRMR{Data: data, Method: "mi-mi", Prune: "lazy", RedundancyFunc: signed}
+	paras.MRMR()
+}
+
+func TestResumeLazyPruning(t *testing.T) {
+	data := generateDiscrete(200, 80)
+	path := filepath.Join(t.TempDir(), "run.ckpt")
+
+	full := mRMR.ParasmRMR{Data: data, Method: "mi-mi", MaxFeatures: 12, Prune: "lazy"}
+	expected, _, _ := full.MRMR()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	interrupted := full
+	interrupted.CheckpointPath = path
+	interrupted.CheckpointEvery = 2
+	interrupted.Progress = func(event mRMR.ProgressEvent) {
+		if event.Step == 6 {
+			cancel()
+		}
+	}
+
+	if _, _, _, err := interrupted.MRMRContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+
+	resumed := mRMR.ParasmRMR{Data: data}
+	result, _, _, err := resumed.Resume(context.Background(), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if fmt.Sprint(result) != fmt.Sprint(expected) {
+		t.Errorf("Resumed run differs: expected %v, got %v", expected, result)
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"github.com/PQMark/mRMR"
+	"reflect"
+	"testing"
+)
+
+func TestMRMRIdempotent(t *testing.T) {
+	cases := []mRMR.ParasmRMR{
+		{Method: mRMR.MethodNMINMI},
+		{Method: mRMR.MethodMIMI, Discretization: true},
+		{Method: mRMR.MethodFSPearson},
+		{Method: mRMR.MethodFSPearson, Calculation: mRMR.CalculationQuo, RedundancyMethod: mRMR.RedundancyMax},
+	}
+
+	for _, paras := range cases {
+		data := GenerateData(500)
+		original := copyData(data)
+		paras.Data = data
+
+		selected1, relevance1, redundancy1 := paras.MRMR()
+		selected2, relevance2, redundancy2 := paras.MRMR()
+
+		if fmt.Sprint(selected1, relevance1) != fmt.Sprint(selected2, relevance2) || !reflect.DeepEqual(redundancy1, redundancy2) {
+			t.Errorf("%s: repeated runs differ: %v and %v", paras.Method, selected1, selected2)
+		}
+
+		if !reflect.DeepEqual(data, original) {
+			t.Errorf("%s: input data was modified", paras.Method)
+		}
+	}
+}
+
+func TestFunctionsReadOnly(t *testing.T) {
+	data := GenerateData(200)
+	feature := column(data.X, 0)
+	other := column(data.X, 1)
+
+	tests := []struct {
+		name string
+		call func()
+	}{
+		{"FStatistic", func() { mRMR.FStatistic(feature, data.Class) }},
+		{"PearsonCorrelation", func() { mRMR.PearsonCorrelation(feature, other) }},
+		{"MutualInfo", func() { mRMR.MutualInfo(feature, other) }},
+		{"Relevance", func() { mRMR.Relevance(data.X, data.Class, mRMR.FStatistic) }},
+		{"Discretization", func() { mRMR.Discretization(data.X, 10) }},
+		{"MinMaxNormalization", func() { mRMR.MinMaxNormalization(feature) }},
+		{"CostAdjustment", func() { mRMR.CostAdjustment(feature, other, 0.5, "penalty") }},
+		{"Delete", func() { mRMR.Delete(feature, 3) }},
+	}
+
+	for _, tt := range tests {
+		originalData := copyData(data)
+		originalFeature := append([]float64(nil), feature...)
+		originalOther := append([]float64(nil), other...)
+
+		tt.call()
+
+		if !reflect.DeepEqual(data, originalData) ||
+			!reflect.DeepEqual(feature, originalFeature) ||
+			!reflect.DeepEqual(other, originalOther) {
+			t.Errorf("%s modified its input", tt.name)
+		}
+	}
+}
+
+func copyData(data mRMR.DatamRMR) mRMR.DatamRMR {
+	X := make([][]float64, len(data.X))
+	for i, row := range data.X {
+		X[i] = append([]float64(nil), row...)
+	}
+
+	return mRMR.DatamRMR{X: X, Class: append([]int(nil), data.Class...)}
+}
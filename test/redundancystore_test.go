@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"github.com/PQMark/mRMR"
+	"math"
+	"testing"
+)
+
+func TestRedundancyStore(t *testing.T) {
+	const epsilon = 1e-9
+
+	store := mRMR.NewRedundancyStore(4, 8)
+
+	store.Add(0, []int{1, 2, 3}, []float64{0.5, 0.1, 0.2})
+	store.Add(2, []int{1, 3}, []float64{0.3, 0.4})
+
+	if val, ok := store.Get(0, 1); !ok || val != 0.5 {
+		t.Errorf("Expected 0.5 for {0, 1}, got %v %v", val, ok)
+	}
+
+	if _, ok := store.Get(2, 2); ok {
+		t.Errorf("Redundancy {2, 2} was never computed but is reported as stored")
+	}
+
+	if math.Abs(store.Sum(1)-0.8) > epsilon || store.Max(1) != 0.5 || math.Abs(store.Sum(3)-0.6) > epsilon || store.Max(3) != 0.4 {
+		t.Errorf("Unexpected running values: sum %v %v, max %v %v", store.Sum(1), store.Sum(3), store.Max(1), store.Max(3))
+	}
+
+	// a third row exceeds the cap of 8 values, so the oldest row is dropped
+	store.Add(1, []int{3}, []float64{0.9})
+
+	if _, ok := store.Get(0, 1); ok {
+		t.Errorf("Oldest row should have been dropped")
+	}
+
+	if store.Count() != 3 || store.Max(3) != 0.9 || math.Abs(store.Sum(1)-0.8) > epsilon {
+		t.Errorf("Running values must cover dropped rows: count %d, max %v, sum %v", store.Count(), store.Max(3), store.Sum(1))
+	}
+
+	if m := store.Map(); len(m) != 3 {
+		t.Errorf("Expected 3 stored values, got %v", m)
+	}
+}
+
+func TestMRMRRedundancyMemory(t *testing.T) {
+	data := GenerateData(2000)
+
+	full := mRMR.ParasmRMR{
+		Data:           data,
+		Method:         "mi-mi",
+		Discretization: true,
+		BinSize:        10,
+	}
+	capped := full
+	capped.RedundancyMemory = 6
+
+	expected, _, fullMap := full.MRMR()
+	result, _, cappedMap := capped.MRMR()
+
+	if fmt.Sprint(result) != fmt.Sprint(expected) {
+		t.Errorf("Memory cap changed the selection: expected %v, got %v", expected, result)
+	}
+
+	if len(cappedMap) > 6 || len(cappedMap) >= len(fullMap) {
+		t.Errorf("Expected at most 6 values with the cap, got %d (uncapped %d)", len(cappedMap), len(fullMap))
+	}
+}
+
+func TestRedundancyStoreSmallCap(t *testing.T) {
+	// a cap below one row still keeps the latest row
+	store := mRMR.NewRedundancyStore(4, 2)
+
+	store.Add(0, []int{1, 2, 3}, []float64{0.5, 0.1, 0.2})
+	store.Add(2, []int{1, 3}, []float64{0.3, 0.4})
+
+	if _, ok := store.Get(0, 1); ok {
+		t.Errorf("Older row should have been dropped")
+	}
+
+	if val, ok := store.Get(2, 3); !ok || val != 0.4 {
+		t.Errorf("Expected 0.4 for {2, 3}, got %v %v", val, ok)
+	}
+
+	if m := store.Map(); len(m) != 2 {
+		t.Errorf("Expected the 2 values of the latest row, got %v", m)
+	}
+}
+
+func TestRedundancyStoreNegativeMax(t *testing.T) {
+	store := mRMR.NewRedundancyStore(3, 0)
+
+	store.Add(0, []int{1, 2}, []float64{-0.3, 0.2})
+	store.Add(2, []int{1}, []float64{-0.1})
+
+	if store.Max(1) != -0.1 || store.Max(2) != 0.2 {
+		t.Errorf("Expected maxima -0.1 and 0.2, got %v and %v", store.Max(1), store.Max(2))
+	}
+}
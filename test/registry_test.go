@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"github.com/PQMark/mRMR"
+	"math"
+	"strings"
+	"testing"
+)
+
+// gapRuns counts the runs of TestRegisterMethod, as with -count.
+var gapRuns int
+
+func TestRegisterMethod(t *testing.T) {
+	// relevance is the absolute difference of class means, redundancy is ignored
+	meanGap := func(feature []float64, class []int) float64 {
+		sum := [2]float64{}
+		count := [2]float64{}
+		for i, c := range class {
+			sum[c] += feature[i]
+			count[c]++
+		}
+		return math.Abs(sum[1]/count[1] - sum[0]/count[0])
+	}
+	noRedundancy := func([]float64, []float64) float64 { return 0 }
+
+	// names stay registered for the life of the process, so each run of the test takes its own
+	gapRuns++
+	name := fmt.Sprintf("Gap-None-%d", gapRuns)
+	mRMR.RegisterMethod(name, mRMR.MethodSpec{Relevance: meanGap, Redundancy: noRedundancy})
+
+	if _, ok := mRMR.LookupMethod(strings.ToLower(name)); !ok {
+		t.Fatalf("Registered method not found, have %v", mRMR.Methods())
+	}
+
+	paras := mRMR.ParasmRMR{
+		Data:   GenerateData(1000),
+		Method: mRMR.Method(strings.ToLower(name)),
+	}
+
+	selectedFeatures, relevance, _ := paras.MRMR()
+
+	// without redundancy the ranking is by relevance alone
+	for i := 1; i < len(selectedFeatures); i++ {
+		if relevance[selectedFeatures[i]] > relevance[selectedFeatures[i-1]] {
+			t.Errorf("Expected features ordered by relevance, got %v (relevance %v)", selectedFeatures, relevance)
+			break
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected a panic when registering a taken name")
+		}
+	}()
+	mRMR.RegisterMethod("mi-mi", mRMR.MethodSpec{Relevance: meanGap, Redundancy: noRedundancy})
+}
+
+func TestCustomRelevanceFunc(t *testing.T) {
+	// a relevance that values the first instance's value, which is largest for feature 5
+	firstValue := func(feature []float64, class []int) float64 {
+		return feature[0]
+	}
+
+	data := GenerateData(1000)
+	for _, row := range data.X {
+		row[5] = 100
+	}
+
+	paras := mRMR.ParasmRMR{
+		Data:          data,
+		Method:        "mi-mi",
+		RelevanceFunc: firstValue,
+		MaxFeatures:   1,
+	}
+
+	selectedFeatures, _, _ := paras.MRMR()
+
+	if len(selectedFeatures) != 1 || selectedFeatures[0] != 5 {
+		t.Errorf("Expected the custom relevance to pick feature 5, got %v", selectedFeatures)
+	}
+}
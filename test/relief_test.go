@@ -0,0 +1,91 @@
+package main
+
+import (
+	"github.com/PQMark/mRMR"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// generateXOR returns binary features where the class is the XOR of features 0 and 1,
+// a numeric feature 2 that is noise, and binary noise features after it.
+func generateXOR(nSamples, nFeatures int) mRMR.DatamRMR {
+	r := rand.New(rand.NewSource(9))
+
+	X := make([][]float64, nSamples)
+	class := make([]int, nSamples)
+	for i := range X {
+		X[i] = make([]float64, nFeatures)
+		for j := range X[i] {
+			X[i][j] = float64(r.Intn(2))
+		}
+		X[i][2] = r.Float64()
+
+		class[i] = int(X[i][0]) ^ int(X[i][1])
+	}
+
+	return mRMR.DatamRMR{X: X, Class: class}
+}
+
+func TestRelief(t *testing.T) {
+	data := generateXOR(400, 8)
+
+	// univariate relevance cannot see the interaction
+	mi := mRMR.Relevance(data.X, data.Class, mRMR.MutualInfo[float64, int])
+	if mi[0] > 0.01 || mi[1] > 0.01 {
+		t.Fatalf("Expected no univariate relevance for the XOR features, got %v", mi)
+	}
+
+	tests := []struct {
+		name   string
+		scores []float64
+	}{
+		{"ReliefF", mRMR.ReliefF(data.X, data.Class, 10, 0, 1)},
+		{"ReliefF sampled", mRMR.ReliefF(data.X, data.Class, 10, 100, 1)},
+		{"MultiSURF", mRMR.MultiSURF(data.X, data.Class, 0, 1)},
+	}
+
+	for _, tt := range tests {
+		if top := topTwo(tt.scores); top != [2]int{0, 1} {
+			t.Errorf("%s: expected features 0 and 1 on top, got %v (scores %v)", tt.name, top, tt.scores)
+		}
+	}
+}
+
+func TestReliefSelection(t *testing.T) {
+	for _, relief := range []string{"relieff", "multisurf"} {
+		paras, err := mRMR.New(generateXOR(400, 8),
+			mRMR.WithMethod(mRMR.MethodMIMI),
+			mRMR.WithRelief(relief, 0, 0),
+			mRMR.WithMaxFeatures(2),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		selectedFeatures, _, _ := paras.MRMR()
+		sort.Ints(selectedFeatures)
+		if len(selectedFeatures) != 2 || selectedFeatures[0] != 0 || selectedFeatures[1] != 1 {
+			t.Errorf("%s: expected features [0 1], got %v", relief, selectedFeatures)
+		}
+	}
+
+	if _, err := mRMR.New(generateXOR(10, 3), mRMR.WithRelief("surf", 0, 0)); err == nil {
+		t.Errorf("Expected an unknown relief to be reported")
+	}
+}
+
+func topTwo(scores []float64) [2]int {
+	order := make([]int, len(scores))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return scores[order[a]] > scores[order[b]] })
+
+	top := [2]int{order[0], order[1]}
+	if top[0] > top[1] {
+		top[0], top[1] = top[1], top[0]
+	}
+
+	return top
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"github.com/PQMark/mRMR"
+	"math/rand"
+	"testing"
+)
+
+// naiveMRMR is the mi-mi selection loop that rescans every selected feature for every candidate.
+// It is the reference the incremental accumulation is checked and benchmarked against.
+func naiveMRMR(data [][]float64, class []int, maxFeatures int) []int {
+	relevanceAll := mRMR.Relevance(data, class, mRMR.MutualInfo[float64, int])
+
+	featuresToConsider := []int{}
+	for i, val := range relevanceAll {
+		if val > 0 {
+			featuresToConsider = append(featuresToConsider, i)
+		}
+	}
+
+	selectedFeatures := []int{}
+	redundancyMap := make(map[[2]int]float64)
+
+	for c := 0; c < maxFeatures; c++ {
+		relevance := make([]float64, len(featuresToConsider))
+		redundancy := make([]float64, len(featuresToConsider))
+
+		for i, f := range featuresToConsider {
+			relevance[i] = relevanceAll[f]
+		}
+
+		if c != 0 {
+			lastSelectedF := selectedFeatures[len(selectedFeatures)-1]
+			redundancyMap = mRMR.RedundancyUpdate(data, featuresToConsider, lastSelectedF, redundancyMap, mRMR.MutualInfo[float64, float64])
+
+			for i, f := range featuresToConsider {
+				s := 0.0
+				for _, sel := range selectedFeatures {
+					s += redundancyMap[[2]int{sel, f}]
+				}
+				redundancy[i] = s / float64(len(selectedFeatures))
+			}
+		}
+
+		score := mRMR.PairwiseOperation(relevance, redundancy, "diff")
+		if mRMR.CheckIfAllNegative(score) {
+			break
+		}
+
+		idx := 0
+		for i, val := range score {
+			if val > score[idx] {
+				idx = i
+			}
+		}
+
+		selectedFeatures = append(selectedFeatures, featuresToConsider[idx])
+		featuresToConsider = mRMR.Delete(featuresToConsider, idx)
+	}
+
+	return selectedFeatures
+}
+
+func TestIncrementalSelection(t *testing.T) {
+	data := generateDiscrete(300, 60)
+
+	paras := mRMR.ParasmRMR{
+		Data:        data,
+		Method:      "mi-mi",
+		MaxFeatures: 40,
+	}
+
+	result, _, _ := paras.MRMR()
+	expected := naiveMRMR(data.X, data.Class, 40)
+
+	if fmt.Sprint(result) != fmt.Sprint(expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func BenchmarkSelection(b *testing.B) {
+	data := generateDiscrete(100, 500)
+
+	for _, maxFeatures := range []int{10, 100, 300} {
+		b.Run(fmt.Sprintf("incremental/k=%d", maxFeatures), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				paras := mRMR.ParasmRMR{
+					Data:        data,
+					Method:      "mi-mi",
+					MaxFeatures: maxFeatures,
+				}
+				paras.MRMR()
+			}
+		})
+
+		b.Run(fmt.Sprintf("rescan/k=%d", maxFeatures), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				naiveMRMR(data.X, data.Class, maxFeatures)
+			}
+		})
+	}
+}
+
+// generateDiscrete returns features with a few levels each, loosely tied to a binary class.
+func generateDiscrete(nSamples, nFeatures int) mRMR.DatamRMR {
+	r := rand.New(rand.NewSource(66))
+
+	X := make([][]float64, nSamples)
+	class := make([]int, nSamples)
+
+	for i := range X {
+		class[i] = r.Intn(2)
+		X[i] = make([]float64, nFeatures)
+
+		for j := range X[i] {
+			X[i][j] = float64(r.Intn(4))
+			if r.Float64() < 0.3 {
+				X[i][j] = float64(class[i] * 3)
+			}
+		}
+	}
+
+	return mRMR.DatamRMR{X: X, Class: class}
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"fmt"
+	"github.com/PQMark/mRMR"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestNewSparseCSR(t *testing.T) {
+	// [[1 0 2]
+	//  [0 0 3]
+	//  [4 5 0]]
+	m := mRMR.NewSparseCSR(3, 3, []int{0, 2, 3, 5}, []int{0, 2, 2, 0, 1}, []float64{1, 2, 3, 4, 5})
+
+	expectedPtr := []int{0, 2, 3, 5}
+	expectedIdx := []int{0, 2, 2, 0, 1}
+	expectedVal := []float64{1, 4, 5, 2, 3}
+
+	for i := range expectedPtr {
+		if m.ColPtr[i] != expectedPtr[i] {
+			t.Fatalf("Expected colPtr %v, got %v", expectedPtr, m.ColPtr)
+		}
+	}
+
+	for i := range expectedIdx {
+		if m.RowIdx[i] != expectedIdx[i] || m.Values[i] != expectedVal[i] {
+			t.Fatalf("Expected rows %v values %v, got %v %v", expectedIdx, expectedVal, m.RowIdx, m.Values)
+		}
+	}
+}
+
+func TestSparseMeasures(t *testing.T) {
+	const epsilon = 1e-9
+
+	data, class := generateCounts(500, 4)
+	m := mRMR.SparseFromDense(data)
+
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 4; j++ {
+			a, b := m.Col(i), m.Col(j)
+
+			expected := mRMR.MutualInfo(column(data, i), column(data, j))
+			if result := mRMR.SparseMutualInfo(a, b); math.Abs(result-expected) > epsilon {
+				t.Errorf("MI(%d, %d): expected %v, got %v", i, j, expected, result)
+			}
+
+			if i != j {
+				expected = mRMR.PearsonCorrelation(column(data, i), column(data, j))
+				if result := mRMR.SparsePearsonCorrelation(a, b); math.Abs(result-expected) > epsilon {
+					t.Errorf("Pearson(%d, %d): expected %v, got %v", i, j, expected, result)
+				}
+			}
+		}
+
+		expected := mRMR.MutualInfo(column(data, i), class)
+		if result := mRMR.SparseClassMutualInfo(m.Col(i), class); math.Abs(result-expected) > epsilon {
+			t.Errorf("class MI(%d): expected %v, got %v", i, expected, result)
+		}
+
+		expected = mRMR.FStatistic(column(data, i), class)
+		if result := mRMR.SparseFStatistic(m.Col(i), class); math.Abs(result-expected) > 1e-6 {
+			t.Errorf("F(%d): expected %v, got %v", i, expected, result)
+		}
+	}
+}
+
+func TestSparseMeasuresLargeMean(t *testing.T) {
+	r := rand.New(rand.NewSource(5))
+
+	// a spread of about 1 around 1e8, where subtracting squared sums loses every digit
+	data := make([][]float64, 200)
+	for i := range data {
+		x := r.Float64()
+		data[i] = []float64{1e8 + x, 1e8 + x + r.Float64()/2}
+		if i%10 == 0 {
+			data[i][1] = 0
+		}
+	}
+
+	m := mRMR.SparseFromDense(data)
+
+	expected := mRMR.PearsonCorrelation(column(data, 0), column(data, 1))
+	if result := mRMR.SparsePearsonCorrelation(m.Col(0), m.Col(1)); math.Abs(result-expected) > 1e-9 {
+		t.Errorf("Pearson: expected %v, got %v", expected, result)
+	}
+
+}
+
+func TestMRMRSparse(t *testing.T) {
+	data, class := generateCounts(1000, 8)
+
+	for _, method := range []mRMR.Method{"mi-mi", "fs-pearson", "nmi-nmi"} {
+		dense := mRMR.ParasmRMR{
+			Data:   mRMR.DatamRMR{X: data, Class: class},
+			Method: method,
+		}
+		sparse := mRMR.ParasmRMR{
+			Data:   mRMR.DatamRMR{Sparse: mRMR.SparseFromDense(data), Class: class},
+			Method: method,
+		}
+
+		expected, expectedRelevance, _ := dense.MRMR()
+		result, relevance, _ := sparse.MRMR()
+
+		if len(result) != len(expected) {
+			t.Fatalf("%s: expected %v, got %v", method, expected, result)
+		}
+
+		for j := range expectedRelevance {
+			if math.Abs(relevance[j]-expectedRelevance[j]) > 1e-9 {
+				t.Errorf("%s: relevance of feature %d is %v, expected %v", method, j, relevance[j], expectedRelevance[j])
+				break
+			}
+		}
+
+		for i := range expected {
+			if result[i] != expected[i] {
+				t.Errorf("%s: expected %v, got %v", method, expected, result)
+				break
+			}
+		}
+	}
+}
+
+func TestMRMRSparseDensified(t *testing.T) {
+	data, class := generateCounts(600, 8)
+
+	// a relevance that prefers the last feature, which only the custom measure knows about
+	lastFirst := func(feature []float64, class []int) float64 {
+		return mRMR.MutualInfo(feature, class) + feature[len(feature)-1]
+	}
+
+	absDiff := func(data1, data2 []float64) float64 {
+		sum := 0.0
+		for i := range data1 {
+			sum += math.Abs(data1[i] - data2[i])
+		}
+		return -sum / float64(len(data1))
+	}
+
+	cases := []mRMR.ParasmRMR{
+		{Method: "fs-spearman"},
+		{Method: "mi-mi", RelevanceFunc: lastFirst},
+		{Method: "fs-pearson", RedundancyFunc: absDiff},
+		{Method: "nmi-nmi", RedundancyFunc: absDiff},
+	}
+
+	for _, paras := range cases {
+		dense, sparse := paras, paras
+		dense.Data = mRMR.DatamRMR{X: data, Class: class}
+		sparse.Data = mRMR.DatamRMR{Sparse: mRMR.SparseFromDense(data), Class: class}
+
+		expected, _, _ := dense.MRMR()
+		result, _, _ := sparse.MRMR()
+
+		if fmt.Sprint(result) != fmt.Sprint(expected) {
+			t.Errorf("%s: expected %v, got %v", paras.Method, expected, result)
+		}
+	}
+}
+
+// generateCounts returns mostly-zero count features, the first of which drives the class.
+func generateCounts(nSamples, nFeatures int) ([][]float64, []int) {
+	r := rand.New(rand.NewSource(66))
+
+	X := make([][]float64, nSamples)
+	class := make([]int, nSamples)
+
+	for i := range X {
+		X[i] = make([]float64, nFeatures)
+		for j := range X[i] {
+			if r.Float64() < 0.2 {
+				X[i][j] = float64(1 + r.Intn(3))
+			}
+		}
+
+		if X[i][0] > 0 || r.Float64() < 0.1 {
+			class[i] = 1
+		}
+	}
+
+	return X, class
+}
+
+func column(data [][]float64, j int) []float64 {
+	col := make([]float64, len(data))
+
+	for i, row := range data {
+		col[i] = row[j]
+	}
+
+	return col
+}
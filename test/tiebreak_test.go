@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"github.com/PQMark/mRMR"
+	"math"
+	"testing"
+)
+
+// duplicateData returns data whose features 1 and 4 are identical, so their scores always tie.
+func duplicateData() mRMR.DatamRMR {
+	data := generateDiscrete(300, 6)
+
+	for i, row := range data.X {
+		row[1] = float64(data.Class[i] * 2)
+		row[4] = row[1]
+	}
+
+	return data
+}
+
+func TestTieBreak(t *testing.T) {
+	paras := mRMR.ParasmRMR{
+		Data:        duplicateData(),
+		Method:      "mi-mi",
+		MaxFeatures: 1,
+	}
+
+	selectedFeatures, relevance, _ := paras.MRMR()
+
+	if relevance[1] != relevance[4] || selectedFeatures[0] != 1 {
+		t.Errorf("Expected the lowest of tied features 1 and 4, got %v (relevance %v)", selectedFeatures, relevance)
+	}
+
+	picked := map[int]bool{}
+	for seed := int64(0); seed < 20; seed++ {
+		first := mRMR.ParasmRMR{Data: duplicateData(), Method: "mi-mi", MaxFeatures: 1, TieBreak: "random", Seed: seed}
+		second := first
+
+		a, _, _ := first.MRMR()
+		b, _, _ := second.MRMR()
+
+		if a[0] != b[0] {
+			t.Errorf("Seed %d picked %d and then %d", seed, a[0], b[0])
+		}
+		picked[a[0]] = true
+	}
+
+	if len(picked) != 2 || !picked[1] || !picked[4] {
+		t.Errorf("Expected random tie breaking to pick both tied features across seeds, got %v", picked)
+	}
+}
+
+func TestParallelReproducible(t *testing.T) {
+	data := GenerateData(1000)
+
+	for _, method := range []mRMR.Method{"mi-mi", "fs-pearson", "nmi-nmi"} {
+		serial := mRMR.ParasmRMR{Data: data, Method: method, Discretization: method == "mi-mi"}
+		expected, expectedRelevance, expectedMap := serial.MRMR()
+
+		for _, workers := range []int{1, 2, 8} {
+			parallel := mRMR.ParasmRMR{Data: data, Method: method, Discretization: method == "mi-mi", Workers: workers}
+			result, relevance, redundancyMap := parallel.MRMR()
+
+			if fmt.Sprint(result) != fmt.Sprint(expected) {
+				t.Errorf("%s with %d workers: expected %v, got %v", method, workers, expected, result)
+			}
+
+			for i := range expectedRelevance {
+				if math.Float64bits(relevance[i]) != math.Float64bits(expectedRelevance[i]) {
+					t.Errorf("%s with %d workers: relevance %d is %v, expected %v", method, workers, i, relevance[i], expectedRelevance[i])
+				}
+			}
+
+			if len(redundancyMap) != len(expectedMap) {
+				t.Fatalf("%s with %d workers: expected %d redundancy values, got %d", method, workers, len(expectedMap), len(redundancyMap))
+			}
+			for key, val := range expectedMap {
+				if math.Float64bits(redundancyMap[key]) != math.Float64bits(val) {
+					t.Errorf("%s with %d workers: redundancy %v is %v, expected %v", method, workers, key, redundancyMap[key], val)
+				}
+			}
+		}
+	}
+}
+
+func TestInvalidTieBreak(t *testing.T) {
+	// the policy is checked up front, not only when scores happen to tie
+	paras := mRMR.ParasmRMR{Data: GenerateData(200), TieBreak: "highest-index"}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected a panic for an unknown tie break")
+		}
+	}()
+	paras.MRMR()
+}
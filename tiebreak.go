@@ -0,0 +1,42 @@
+package mRMR
+
+import "math/rand"
+
+// chooseFeature returns the position in candidates of the best score, breaking exact ties by policy:
+// "lowest-index" takes the smallest feature index, "relevance" the highest relevance (then the smallest index),
+// and "random" a tied feature drawn from a generator seeded with seed and the step, so runs are reproducible.
+func chooseFeature(score []float64, candidates []int, relevance []float64, policy string, seed int64, step int) int {
+	best := score[getMaxIndex(score)]
+
+	ties := make([]int, 0, 1)
+	for i, val := range score {
+		if val == best {
+			ties = append(ties, i)
+		}
+	}
+
+	idx := ties[0]
+	for _, i := range ties[1:] {
+		switch policy {
+		case "lowest-index", "random":
+			if candidates[i] < candidates[idx] {
+				idx = i
+			}
+		case "relevance":
+			if relevance[candidates[i]] > relevance[candidates[idx]] ||
+				(relevance[candidates[i]] == relevance[candidates[idx]] && candidates[i] < candidates[idx]) {
+				idx = i
+			}
+		default:
+			panic("Invalid tie break. Choose from 'lowest-index', 'relevance' or 'random'")
+		}
+	}
+
+	if policy == "random" && len(ties) > 1 {
+		// ties are in candidate order, which is ascending by feature index
+		r := rand.New(rand.NewSource(seed + int64(step)))
+		idx = ties[r.Intn(len(ties))]
+	}
+
+	return idx
+}